@@ -0,0 +1,53 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	ui "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/widgets"
+)
+
+func main() {
+	p := widgets.NewParagraph()
+	p.Title = "App"
+
+	g := widgets.NewGauge()
+	g.Title = "Progress"
+
+	grid := ui.NewGrid()
+	grid.Set(
+		ui.NewRow(0.5, p),
+		ui.NewRow(0.5, g),
+	)
+
+	app := ui.NewApp(grid)
+	app.Dispatcher.Bind("q", "quit", func(ui.Event) { app.Stop() })
+	app.Dispatcher.Bind("<C-c>", "quit", func(ui.Event) { app.Stop() })
+	app.Dispatcher.Bind("<Resize>", "", func(e ui.Event) {
+		size := e.Payload.(ui.Resize)
+		p.Text = fmt.Sprintf("Resized to %dx%d, press q to quit", size.Width, size.Height)
+	})
+	p.Text = "Press q to quit"
+
+	go func() {
+		for i := 0; ; i = (i + 1) % 101 {
+			time.Sleep(50 * time.Millisecond)
+			percent := i
+			app.QueueUpdate(func() {
+				g.Percent = percent
+			})
+		}
+	}()
+
+	if err := app.Run(); err != nil {
+		log.Fatalf("failed to run termui: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"log"
+	"time"
+
+	ui "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/widgets"
+)
+
+func main() {
+	p := widgets.NewParagraph()
+	p.Title = "Dialog"
+	p.Text = "Press q to quit"
+
+	g := widgets.NewGauge()
+	g.Title = "Progress"
+
+	grid := ui.NewGrid()
+	grid.Set(
+		ui.NewAutoRow(p),
+		ui.NewFlexRow(1, g),
+	)
+
+	layers := ui.NewLayers(grid)
+	app := ui.NewApp(layers)
+
+	dialog := widgets.NewDialog("Do you really want to quit?", "Quit", "Cancel")
+	dialog.Title = "Confirm"
+	dialog.Shadow = true
+	dialog.OnSelect = func(button int) {
+		if button == 0 {
+			app.Stop()
+		}
+		layers.Remove(dialog)
+	}
+
+	app.Dispatcher.Bind("q", "quit", func(ui.Event) {
+		dialog.SelectedButton = 1
+		layers.Push(ui.LayerModal, dialog)
+	})
+	app.Dispatcher.Bind("<C-c>", "quit", func(ui.Event) { app.Stop() })
+
+	go func() {
+		for i := 0; ; i = (i + 1) % 101 {
+			time.Sleep(50 * time.Millisecond)
+			percent := i
+			app.QueueUpdate(func() {
+				g.Percent = percent
+			})
+		}
+	}()
+
+	if err := app.Run(); err != nil {
+		log.Fatalf("failed to run termui: %v", err)
+	}
+}
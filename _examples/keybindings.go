@@ -0,0 +1,66 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	ui "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/widgets"
+)
+
+func main() {
+	if err := ui.Init(); err != nil {
+		log.Fatalf("failed to initialize termui: %v", err)
+	}
+	defer ui.Close()
+
+	l := widgets.NewList()
+	l.Title = "List"
+	l.Rows = []string{"apples", "bananas", "cherries", "dates", "elderberries", "figs"}
+	l.SetRect(0, 0, 30, 10)
+
+	help := widgets.NewTable()
+	help.Title = "Help"
+	help.SetRect(30, 0, 80, 10)
+
+	status := widgets.NewParagraph()
+	status.SetRect(0, 10, 80, 13)
+
+	quit := false
+	dispatcher := ui.NewDispatcher()
+	dispatcher.Bind("q", "quit", func(ui.Event) { quit = true })
+	dispatcher.Bind("<C-c>", "quit", func(ui.Event) { quit = true })
+	dispatcher.Bind("<C-x> <C-s>", "pretend to save", func(ui.Event) { status.Text = "saved" })
+
+	list := dispatcher.Keymap("list")
+	list.Bind("j", "next row", func(ui.Event) { l.ScrollDown() })
+	list.Bind("k", "previous row", func(ui.Event) { l.ScrollUp() })
+	list.Bind("g g", "first row", func(ui.Event) { l.ScrollTop() })
+	list.Bind("G", "last row", func(ui.Event) { l.ScrollBottom() })
+	dispatcher.SetScopes("list")
+
+	help.Rows = [][]string{{"Scope", "Keys", "Description"}}
+	for _, b := range dispatcher.Bindings() {
+		help.Rows = append(help.Rows, []string{b.Scope, b.Keys, b.Description})
+	}
+
+	ui.Render(l, help, status)
+	for e := range ui.PollEvents() {
+		if e.Type == ui.KeyboardEvent && !dispatcher.Dispatch(e) {
+			status.Text = fmt.Sprintf("%s is not bound", e.ID)
+		}
+		if quit {
+			return
+		}
+		if pending := dispatcher.Pending(); pending != "" {
+			status.Text = pending + " ..."
+		}
+		ui.Render(l, help, status)
+	}
+}
@@ -40,6 +40,7 @@ func main() {
 	tabpane := widgets.NewTabPane("pierwszy", "drugi", "trzeci", "żółw", "four", "five")
 	tabpane.SetRect(0, 1, 50, 4)
 	tabpane.Border = true
+	tabpane.InactiveTabStyle = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierDim)
 
 	renderTab := func() {
 		switch tabpane.ActiveTabIndex {
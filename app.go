@@ -0,0 +1,205 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxFPS is the default frame rate cap of an App.
+const DefaultMaxFPS = 60
+
+// chordExpireInterval is how often an App checks whether a key chord timed out.
+const chordExpireInterval = 100 * time.Millisecond
+
+// App runs the main loop of a program: it polls events, sends them to its Dispatcher,
+// resizes the root Drawable to the terminal and redraws it after every event or update,
+// no more than MaxFPS times per second.
+//
+// Handlers, updates and drawing all run on the goroutine calling Run,
+// other goroutines must use QueueUpdate to change the widgets.
+type App struct {
+	// MaxFPS caps the number of frames drawn per second. Zero or less means no cap.
+	MaxFPS int
+	// Focus, if set, receives the events first, which are only sent to the Dispatcher
+	// if they are not used by the focused widget.
+	// If the root is a Layers with an open modal, the modal receives the events instead.
+	Focus *FocusManager
+	// Dispatcher receives every event, including <Resize> once the root has been resized,
+	// except the keyboard, mouse and paste events captured by an open modal.
+	Dispatcher *Dispatcher
+
+	root Drawable
+	// running is set while Run runs the main loop
+	running bool
+
+	updates []func()
+	notify  chan struct{}
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	sync.Mutex
+}
+
+func NewApp(root Drawable) *App {
+	return &App{
+		MaxFPS:     DefaultMaxFPS,
+		Dispatcher: NewDispatcher(),
+		root:       root,
+		notify:     make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+	}
+}
+
+// SetRoot replaces the Drawable filling the terminal.
+// It must be called from the main loop, e.g. in a handler or with QueueUpdate, once the App runs.
+// Before Run, it only replaces the root, which Run resizes once termui is initialized.
+func (self *App) SetRoot(root Drawable) {
+	self.root = root
+	if self.running {
+		self.resize()
+	}
+}
+
+func (self *App) Root() Drawable {
+	return self.root
+}
+
+// QueueUpdate runs f on the main loop and redraws the root afterwards.
+// It can be called from any goroutine, and updates queued before the next frame are drawn at once.
+func (self *App) QueueUpdate(f func()) {
+	self.Lock()
+	self.updates = append(self.updates, f)
+	self.Unlock()
+	select {
+	case self.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Redraw schedules a redraw of the root. It can be called from any goroutine.
+func (self *App) Redraw() {
+	self.QueueUpdate(func() {})
+}
+
+// Stop makes Run return. It can be called from any goroutine.
+func (self *App) Stop() {
+	self.stopOnce.Do(func() {
+		close(self.stop)
+	})
+}
+
+// resize sets the rect of the root to the terminal dimensions.
+func (self *App) resize() {
+	if self.root == nil {
+		return
+	}
+	width, height := TerminalDimensions()
+	self.root.Lock()
+	self.root.SetRect(0, 0, width, height)
+	self.root.Unlock()
+	Clear()
+}
+
+// runUpdates runs the queued updates.
+func (self *App) runUpdates() {
+	self.Lock()
+	updates := self.updates
+	self.updates = nil
+	self.Unlock()
+	for _, f := range updates {
+		f()
+	}
+}
+
+// handleEvent sends an event to the open modal or the focused widget,
+// then to the Dispatcher if they didn't use it.
+// An open modal captures the input, so the bindings of the Dispatcher don't fire behind it.
+func (self *App) handleEvent(e Event) {
+	if layers, ok := self.root.(*Layers); ok && layers.Modal() != nil {
+		switch e.Type {
+		case KeyboardEvent, MouseEvent, PasteEvent:
+			layers.HandleEvent(e)
+		default:
+			self.Dispatcher.Dispatch(e)
+		}
+		return
+	}
+	if self.Focus == nil || !self.Focus.HandleEvent(e) {
+		self.Dispatcher.Dispatch(e)
+	}
+}
+
+// Run initializes termui, then runs the main loop until Stop is called or the backend fails.
+// termui is closed when Run returns.
+func (self *App) Run() error {
+	if err := Init(); err != nil {
+		return err
+	}
+	defer Close()
+	self.running = true
+	defer func() { self.running = false }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := PollEventsContext(ctx)
+
+	expire := time.NewTicker(chordExpireInterval)
+	defer expire.Stop()
+
+	self.resize()
+
+	dirty := true
+	var lastFrame time.Time
+	var frame <-chan time.Time
+	for {
+		if dirty && frame == nil {
+			var wait time.Duration
+			if self.MaxFPS > 0 {
+				wait = time.Second/time.Duration(self.MaxFPS) - time.Since(lastFrame)
+			}
+			if wait <= 0 {
+				if self.root != nil {
+					Render(self.root)
+				}
+				lastFrame = time.Now()
+				dirty = false
+			} else {
+				frame = time.After(wait)
+			}
+		}
+
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			switch e.Type {
+			case ErrorEvent:
+				if err, ok := e.Payload.(error); ok {
+					return err
+				}
+			case ResizeEvent:
+				self.resize()
+			}
+			self.handleEvent(e)
+			dirty = true
+		case <-self.notify:
+			self.runUpdates()
+			dirty = true
+		case <-expire.C:
+			if self.Dispatcher.Expire() {
+				dirty = true
+			}
+		case <-frame:
+			frame = nil
+		case <-self.stop:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"errors"
+	"image"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAppModalCapturesInput(t *testing.T) {
+	modal := NewBlock()
+	modal.SetRect(2, 2, 6, 6)
+	layers := NewLayers(NewBlock())
+	layers.SetRect(0, 0, 10, 10)
+	layers.Push(LayerModal, modal)
+
+	app := NewApp(layers)
+	fired := []string{}
+	app.Dispatcher.Bind("q", "", func(e Event) { fired = append(fired, e.ID) })
+	app.Dispatcher.Bind("<Resize>", "", func(e Event) { fired = append(fired, e.ID) })
+	app.Dispatcher.Bind("<MouseLeft>", "", func(e Event) { fired = append(fired, e.ID) })
+
+	app.handleEvent(Event{Type: KeyboardEvent, ID: "q"})
+	app.handleEvent(newMouseEvent(Mouse{X: 0, Y: 0, Button: MouseButtonLeft}))
+	app.handleEvent(Event{Type: ResizeEvent, ID: "<Resize>"})
+	if len(fired) != 1 || fired[0] != "<Resize>" {
+		t.Errorf("expected only <Resize> to reach the dispatcher behind a modal, got %q", fired)
+	}
+
+	app.handleEvent(Event{Type: KeyboardEvent, ID: "<Escape>"})
+	if layers.Modal() != nil {
+		t.Fatal("expected <Escape> to close the modal")
+	}
+	app.handleEvent(Event{Type: KeyboardEvent, ID: "q"})
+	if len(fired) != 2 || fired[1] != "q" {
+		t.Errorf("expected q to reach the dispatcher once the modal is closed, got %q", fired)
+	}
+}
+
+// drawCounter is a Block recording when it is drawn.
+type drawCounter struct {
+	Block
+	draws  []time.Time
+	onDraw func()
+}
+
+func (self *drawCounter) Draw(buf *Buffer) {
+	self.draws = append(self.draws, time.Now())
+	if self.onDraw != nil {
+		self.onDraw()
+	}
+}
+
+// runApp runs the App on a SimulationBackend and returns a channel receiving the error of Run.
+func runApp(t *testing.T, app *App, sim *SimulationBackend) <-chan error {
+	SetBackend(sim)
+	done := make(chan error, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		done <- app.Run()
+	}()
+	t.Cleanup(func() {
+		app.Stop()
+		<-finished
+		SetBackend(NewTermboxBackend())
+	})
+	return done
+}
+
+// waitRun returns the error of Run, failing the test if it doesn't return in time.
+func waitRun(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for Run to return")
+	}
+	return nil
+}
+
+func TestAppResize(t *testing.T) {
+	root := &drawCounter{Block: *NewBlock()}
+	app := NewApp(root)
+	resized := make(chan image.Rectangle, 1)
+	app.Dispatcher.Bind("<Resize>", "", func(Event) {
+		resized <- root.GetRect()
+	})
+	sim := NewSimulationBackend(10, 5)
+	runApp(t, app, sim)
+
+	sim.SetSize(4, 2)
+	select {
+	case rect := <-resized:
+		if rect != image.Rect(0, 0, 4, 2) {
+			t.Errorf("expected the root to be resized before the dispatcher is called, got %v", rect)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for <Resize>")
+	}
+}
+
+func TestAppQueueUpdateCoalesces(t *testing.T) {
+	root := &drawCounter{Block: *NewBlock()}
+	app := NewApp(root)
+	const updates = 100
+	ran := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updates/10; j++ {
+				app.QueueUpdate(func() { ran++ })
+			}
+		}()
+	}
+	wg.Wait()
+	root.onDraw = func() {
+		if ran == updates {
+			app.Stop()
+		}
+	}
+
+	err := waitRun(t, runApp(t, app, NewSimulationBackend(10, 5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ran != updates {
+		t.Errorf("expected %d updates to run, got %d", updates, ran)
+	}
+	// the first frame, then a single one for all the queued updates
+	if len(root.draws) != 2 {
+		t.Errorf("expected 2 frames, got %d", len(root.draws))
+	}
+}
+
+func TestAppMaxFPS(t *testing.T) {
+	root := &drawCounter{Block: *NewBlock()}
+	app := NewApp(root)
+	app.MaxFPS = 20
+	root.onDraw = func() {
+		if len(root.draws) == 4 {
+			app.Stop()
+		}
+	}
+	done := runApp(t, app, NewSimulationBackend(10, 5))
+	go func() {
+		for {
+			select {
+			case <-app.stop:
+				return
+			case <-time.After(time.Millisecond):
+				app.Redraw()
+			}
+		}
+	}()
+	if err := waitRun(t, done); err != nil {
+		t.Fatal(err)
+	}
+
+	interval := time.Second / 20
+	for i := 1; i < len(root.draws); i++ {
+		// allow for the resolution of timers
+		if elapsed := root.draws[i].Sub(root.draws[i-1]); elapsed < interval-5*time.Millisecond {
+			t.Errorf("frame %d: expected at least %v since the previous frame, got %v", i, interval, elapsed)
+		}
+	}
+}
+
+func TestAppStop(t *testing.T) {
+	app := NewApp(NewBlock())
+	done := runApp(t, app, NewSimulationBackend(10, 5))
+	go app.Stop()
+	if err := waitRun(t, done); err != nil {
+		t.Errorf("expected Run to return nil once stopped, got %v", err)
+	}
+	// Stop can be called again
+	app.Stop()
+}
+
+func TestAppErrorEvent(t *testing.T) {
+	app := NewApp(NewBlock())
+	sim := NewSimulationBackend(10, 5)
+	done := runApp(t, app, sim)
+	failure := errors.New("backend failure")
+	sim.InjectEvent(Event{Type: ErrorEvent, ID: "<Error>", Payload: failure})
+	if err := waitRun(t, done); err != failure {
+		t.Errorf("expected Run to return %v, got %v", failure, err)
+	}
+}
+
+func TestAppSetRootBeforeRun(t *testing.T) {
+	root := NewBlock()
+	app := NewApp(nil)
+	// doesn't touch the backend, which isn't initialized yet
+	app.SetRoot(root)
+	if app.Root() != root || !root.GetRect().Empty() {
+		t.Fatalf("expected the root to be replaced without being resized, got %v", root.GetRect())
+	}
+
+	resized := make(chan image.Rectangle, 1)
+	app.QueueUpdate(func() { resized <- root.GetRect() })
+	runApp(t, app, NewSimulationBackend(10, 5))
+	select {
+	case rect := <-resized:
+		if rect != image.Rect(0, 0, 10, 5) {
+			t.Errorf("expected Run to resize the root, got %v", rect)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the update")
+	}
+}
@@ -4,32 +4,75 @@
 
 package termui
 
-import (
-	tb "github.com/nsf/termbox-go"
-)
+// Backend is the interface termui uses to talk to the terminal.
+// The default Backend is built on top of termbox-go, but any other implementation
+// can be installed with `SetBackend` before calling `Init`.
+type Backend interface {
+	// Init prepares the terminal for drawing and reading events.
+	Init() error
+	// Close restores the terminal to its original state.
+	Close()
 
-// Init initializes termbox-go and is required to render anything.
+	// Size returns the width and height of the terminal in cells.
+	Size() (int, int)
+	// Sync refreshes the terminal size and forces a full redraw on the next Flush.
+	Sync() error
+
+	// SetCell sets a cell of the back buffer.
+	SetCell(x, y int, c Cell)
+	// Clear clears the back buffer using the given style.
+	Clear(Style)
+	// Flush draws the back buffer to the terminal.
+	Flush() error
+
+	// SetCursor shows the cursor at the given position.
+	SetCursor(x, y int)
+	// HideCursor hides the cursor.
+	HideCursor()
+
+	// PollEvent blocks until an event is available and returns it.
+	// Errors are returned as an ErrorEvent.
+	PollEvent() Event
+	// Interrupt makes a blocked PollEvent return an InterruptEvent.
+	// If PollEvent isn't running, the next call returns the InterruptEvent.
+	Interrupt()
+}
+
+var backend Backend = NewTermboxBackend()
+
+// SetBackend replaces the Backend used by termui.
+// It must be called before `Init`.
+func SetBackend(b Backend) {
+	backend = b
+	Invalidate()
+}
+
+// GetBackend returns the Backend currently used by termui.
+func GetBackend() Backend {
+	return backend
+}
+
+// Init initializes the backend and is required to render anything.
 // After initialization, the library must be finalized with `Close`.
 func Init() error {
-	if err := tb.Init(); err != nil {
-		return err
-	}
-	tb.SetInputMode(tb.InputEsc | tb.InputMouse)
-	tb.SetOutputMode(tb.Output256)
-	return nil
+	Invalidate()
+	return backend.Init()
 }
 
-// Close closes termbox-go.
+// Close stops polling events and closes the backend.
 func Close() {
-	tb.Close()
+	stopPollingEvents()
+	backend.Close()
 }
 
 func TerminalDimensions() (int, int) {
-	tb.Sync()
-	width, height := tb.Size()
+	backend.Sync()
+	width, height := backend.Size()
 	return width, height
 }
 
 func Clear() {
-	tb.Clear(tb.ColorDefault, tb.Attribute(Theme.Default.Bg+1))
+	style := NewStyle(ColorClear, Theme.Default.Bg)
+	backend.Clear(style)
+	resetFrontBuffer(style)
 }
@@ -0,0 +1,269 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	rw "github.com/mattn/go-runewidth"
+)
+
+// SimulationBackend is an in-memory Backend which doesn't need a terminal.
+// It keeps the cells of the last flushed frame and returns injected events from PollEvent,
+// which makes it possible to render and drive widgets in tests.
+type SimulationBackend struct {
+	// ColorMode is used to downgrade the colors of the cells, it defaults to ColorModeTrueColor.
+	ColorMode ColorMode
+
+	width  int
+	height int
+
+	back  []Cell
+	front []Cell
+
+	cursor        image.Point
+	cursorVisible bool
+
+	events chan Event
+
+	sync.Mutex
+}
+
+var _ Backend = (*SimulationBackend)(nil)
+
+func NewSimulationBackend(width, height int) *SimulationBackend {
+	self := &SimulationBackend{
+		ColorMode: ColorModeTrueColor,
+		events:    make(chan Event, 256),
+	}
+	self.resize(width, height)
+	return self
+}
+
+func (self *SimulationBackend) resize(width, height int) {
+	self.width, self.height = width, height
+	self.back = make([]Cell, width*height)
+	self.front = make([]Cell, width*height)
+	for i := range self.back {
+		self.back[i] = CellClear
+		self.front[i] = CellClear
+	}
+}
+
+func (self *SimulationBackend) Init() error {
+	return nil
+}
+
+func (self *SimulationBackend) Close() {}
+
+func (self *SimulationBackend) Size() (int, int) {
+	self.Lock()
+	defer self.Unlock()
+	return self.width, self.height
+}
+
+func (self *SimulationBackend) Sync() error {
+	return nil
+}
+
+func (self *SimulationBackend) SetCell(x, y int, c Cell) {
+	self.Lock()
+	defer self.Unlock()
+	if x < 0 || y < 0 || x >= self.width || y >= self.height {
+		return
+	}
+	c.Style.Fg = c.Style.Fg.Downgrade(self.ColorMode)
+	c.Style.Bg = c.Style.Bg.Downgrade(self.ColorMode)
+	self.back[y*self.width+x] = c
+}
+
+func (self *SimulationBackend) Clear(style Style) {
+	self.Lock()
+	defer self.Unlock()
+	style.Fg = style.Fg.Downgrade(self.ColorMode)
+	style.Bg = style.Bg.Downgrade(self.ColorMode)
+	for i := range self.back {
+		self.back[i] = Cell{' ', style}
+	}
+}
+
+func (self *SimulationBackend) Flush() error {
+	self.Lock()
+	defer self.Unlock()
+	copy(self.front, self.back)
+	return nil
+}
+
+func (self *SimulationBackend) SetCursor(x, y int) {
+	self.Lock()
+	defer self.Unlock()
+	self.cursor = image.Pt(x, y)
+	self.cursorVisible = true
+}
+
+func (self *SimulationBackend) HideCursor() {
+	self.Lock()
+	defer self.Unlock()
+	self.cursorVisible = false
+}
+
+// PollEvent returns the next injected event, blocking until one is available.
+func (self *SimulationBackend) PollEvent() Event {
+	return <-self.events
+}
+
+func (self *SimulationBackend) Interrupt() {
+	self.InjectEvent(Event{
+		Type: InterruptEvent,
+		ID:   "<Interrupt>",
+	})
+}
+
+// InjectEvent queues an event to be returned by PollEvent.
+func (self *SimulationBackend) InjectEvent(e Event) {
+	self.events <- e
+}
+
+// InjectKeys queues a KeyboardEvent for each of the given IDs, e.g. "j" or "<C-c>".
+// The Key payload of the events is parsed from the IDs.
+func (self *SimulationBackend) InjectKeys(ids ...string) {
+	for _, id := range ids {
+		key, _ := ParseKey(id)
+		self.InjectEvent(Event{
+			Type:    KeyboardEvent,
+			ID:      id,
+			Payload: key,
+		})
+	}
+}
+
+// InjectPaste queues a PasteEvent holding the given text.
+func (self *SimulationBackend) InjectPaste(text string) {
+	self.InjectEvent(Event{
+		Type:    PasteEvent,
+		ID:      "<Paste>",
+		Payload: Paste{Text: text},
+	})
+}
+
+// InjectMouse queues a MouseEvent for the given button ID, e.g. "<MouseLeft>" or "<MouseRelease>".
+func (self *SimulationBackend) InjectMouse(id string, x, y int) {
+	m := Mouse{X: x, Y: y}
+	if id == "<MouseRelease>" {
+		m.Action = MouseActionRelease
+	}
+	for button, buttonID := range mouseButtonIDs {
+		if buttonID == id {
+			m.Button = button
+		}
+	}
+	self.InjectMouseEvent(m)
+}
+
+// InjectMouseEvent queues a MouseEvent with the given payload.
+func (self *SimulationBackend) InjectMouseEvent(m Mouse) {
+	self.InjectEvent(newMouseEvent(m))
+}
+
+// SetSize resizes the simulated screen, clearing it, and queues a ResizeEvent.
+func (self *SimulationBackend) SetSize(width, height int) {
+	self.Lock()
+	self.resize(width, height)
+	self.Unlock()
+	self.InjectEvent(Event{
+		Type: ResizeEvent,
+		ID:   "<Resize>",
+		Payload: Resize{
+			Width:  width,
+			Height: height,
+		},
+	})
+}
+
+// GetCell returns the cell at the given position of the last flushed frame.
+func (self *SimulationBackend) GetCell(x, y int) Cell {
+	self.Lock()
+	defer self.Unlock()
+	if x < 0 || y < 0 || x >= self.width || y >= self.height {
+		return CellClear
+	}
+	return self.front[y*self.width+x]
+}
+
+// Cells returns a copy of the last flushed frame in row-major order.
+func (self *SimulationBackend) Cells() []Cell {
+	self.Lock()
+	defer self.Unlock()
+	cells := make([]Cell, len(self.front))
+	copy(cells, self.front)
+	return cells
+}
+
+// Cursor returns the cursor position and whether it is visible.
+func (self *SimulationBackend) Cursor() (image.Point, bool) {
+	self.Lock()
+	defer self.Unlock()
+	return self.cursor, self.cursorVisible
+}
+
+// rows returns the last flushed frame split in rows,
+// skipping the cells covered by the right half of wide runes.
+func (self *SimulationBackend) rows() [][]Cell {
+	self.Lock()
+	defer self.Unlock()
+	rows := make([][]Cell, self.height)
+	for y := 0; y < self.height; y++ {
+		row := []Cell{}
+		for x := 0; x < self.width; x++ {
+			cell := self.front[y*self.width+x]
+			row = append(row, cell)
+			if rw.RuneWidth(cell.Rune) == 2 {
+				x++
+			}
+		}
+		rows[y] = row
+	}
+	return rows
+}
+
+// String returns the last flushed frame as plain text, one line per row.
+func (self *SimulationBackend) String() string {
+	lines := []string{}
+	for _, row := range self.rows() {
+		lines = append(lines, CellsToString(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StyledString returns the last flushed frame as text using the `ParseStyles` markup,
+// e.g. `[Title](fg:red,mod:bold)`. Cells with the clear style are written as plain text.
+func (self *SimulationBackend) StyledString() string {
+	lines := []string{}
+	for _, row := range self.rows() {
+		var sb strings.Builder
+		for i := 0; i < len(row); {
+			style := row[i].Style
+			j := i
+			for j < len(row) && row[j].Style == style {
+				j++
+			}
+			text := EscapeStyles(CellsToString(row[i:j]))
+			if style == StyleClear {
+				sb.WriteString(text)
+			} else {
+				fmt.Fprintf(&sb, "%c%s%c%c%s%c",
+					tokenBeginStyledText, text, tokenEndStyledText,
+					tokenBeginStyle, style.String(), tokenEndStyle,
+				)
+			}
+			i = j
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "\n")
+}
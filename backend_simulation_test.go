@@ -0,0 +1,147 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+func TestSimulationBackendRender(t *testing.T) {
+	sim := NewSimulationBackend(8, 4)
+	SetBackend(sim)
+	defer SetBackend(NewTermboxBackend())
+
+	block := NewBlock()
+	block.Title = "a"
+	block.SetRect(0, 0, 5, 3)
+	red := NewBlock()
+	red.BorderStyle = NewStyle(ColorRed)
+	red.SetRect(5, 1, 8, 4)
+	Render(block, red)
+
+	expected := strings.Join([]string{
+		"┌─a─┐   ",
+		"│   │┌─┐",
+		"└───┘│ │",
+		"     └─┘",
+	}, "\n")
+	if s := sim.String(); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+
+	styled := strings.Join([]string{
+		"[┌─a─┐](fg:white)   ",
+		"[│](fg:white)   [│](fg:white)[┌─┐](fg:red)",
+		"[└───┘](fg:white)[│](fg:red) [│](fg:red)",
+		"     [└─┘](fg:red)",
+	}, "\n")
+	if s := sim.StyledString(); s != styled {
+		t.Errorf("expected:\n%s\ngot:\n%s", styled, s)
+	}
+	if cell := sim.GetCell(2, 0); cell != NewCell('a', NewStyle(ColorWhite)) {
+		t.Errorf("expected the title cell, got %+v", cell)
+	}
+	if cell := sim.GetCell(8, 0); cell != CellClear {
+		t.Errorf("expected CellClear outside the screen, got %+v", cell)
+	}
+	if cells := sim.Cells(); len(cells) != 8*4 || cells[8*3+6] != NewCell('─', NewStyle(ColorRed)) {
+		t.Errorf("unexpected cells %v", cells)
+	}
+}
+
+func TestSimulationBackendFlush(t *testing.T) {
+	sim := NewSimulationBackend(3, 1)
+	sim.SetCell(0, 0, NewCell('x'))
+	if s := sim.String(); s != "   " {
+		t.Errorf("expected cells to be hidden until Flush, got %q", s)
+	}
+	sim.Flush()
+	if s := sim.String(); s != "x  " {
+		t.Errorf("expected %q, got %q", "x  ", s)
+	}
+}
+
+func TestSimulationBackendColorMode(t *testing.T) {
+	sim := NewSimulationBackend(1, 1)
+	sim.ColorMode = ColorMode16
+	sim.SetCell(0, 0, NewCell('x', NewStyle(ColorRGB(255, 0, 0), Color(196))))
+	sim.Flush()
+	if style := sim.GetCell(0, 0).Style; style.Fg >= 16 || style.Bg >= 16 {
+		t.Errorf("expected 16 colors, got %+v", style)
+	}
+}
+
+func TestSimulationBackendEvents(t *testing.T) {
+	sim := NewSimulationBackend(10, 5)
+	SetBackend(sim)
+	defer SetBackend(NewTermboxBackend())
+	defer Close()
+	events := PollEvents()
+
+	sim.InjectKeys("j", "<C-c>", "<M-<Up>>")
+	keys := []Key{
+		{Code: KeyRune, Rune: 'j'},
+		{Code: KeyRune, Rune: 'c', Mod: ModCtrl},
+		{Code: KeyUp, Mod: ModAlt},
+	}
+	for _, key := range keys {
+		e, _ := receive(t, events)
+		if e.Type != KeyboardEvent || e.ID != key.String() || e.Payload != key {
+			t.Errorf("expected %q, got %+v", key.String(), e)
+		}
+	}
+
+	sim.InjectMouse("<MouseLeft>", 3, 4)
+	e, _ := receive(t, events)
+	if m, ok := e.Payload.(Mouse); e.Type != MouseEvent || e.ID != "<MouseLeft>" || !ok ||
+		m.X != 3 || m.Y != 4 || m.Button != MouseButtonLeft {
+		t.Errorf("expected a left click at 3, 4, got %+v", e)
+	}
+	sim.InjectMouse("<MouseRelease>", 3, 4)
+	e, _ = receive(t, events)
+	if m, ok := e.Payload.(Mouse); e.ID != "<MouseRelease>" || !ok || m.Action != MouseActionRelease {
+		t.Errorf("expected a release, got %+v", e)
+	}
+
+	sim.InjectPaste("hello")
+	e, _ = receive(t, events)
+	if e.Type != PasteEvent || e.Payload != (Paste{Text: "hello"}) {
+		t.Errorf("expected a paste, got %+v", e)
+	}
+
+	sim.SetSize(4, 2)
+	e, _ = receive(t, events)
+	if e.Type != ResizeEvent || e.Payload != (Resize{Width: 4, Height: 2}) {
+		t.Errorf("expected a resize, got %+v", e)
+	}
+	if width, height := TerminalDimensions(); width != 4 || height != 2 {
+		t.Errorf("expected 4x2, got %dx%d", width, height)
+	}
+	block := NewBlock()
+	block.SetRect(0, 0, 4, 2)
+	Render(block)
+	if s := sim.String(); s != "┌──┐\n└──┘" {
+		t.Errorf("expected the block to fill the resized screen, got:\n%s", s)
+	}
+}
+
+func TestSimulationBackendCursor(t *testing.T) {
+	sim := NewSimulationBackend(10, 5)
+	if _, visible := sim.Cursor(); visible {
+		t.Error("expected the cursor to be hidden initially")
+	}
+	sim.SetCursor(3, 2)
+	if p, visible := sim.Cursor(); p != image.Pt(3, 2) || !visible {
+		t.Errorf("expected a visible cursor at (3,2), got %v, %v", p, visible)
+	}
+	sim.HideCursor()
+	if p, visible := sim.Cursor(); p != image.Pt(3, 2) || visible {
+		t.Errorf("expected a hidden cursor at (3,2), got %v, %v", p, visible)
+	}
+}
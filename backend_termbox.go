@@ -0,0 +1,341 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	tb "github.com/nsf/termbox-go"
+)
+
+// TermboxBackend is the default Backend, built on top of termbox-go.
+// Colors are downgraded to the 256 color palette, or to 16 colors if the terminal doesn't
+// support 256 colors, which keeps the palette of the terminal.
+// 24-bit colors are opt-in with SetColorMode(ColorModeTrueColor): termbox then sends every color
+// as RGB, using the values of the xterm default palette for palette colors.
+type TermboxBackend struct {
+	colorMode    ColorMode
+	colorModeSet bool
+	// input holds raw input which hasn't been converted to events yet
+	input []byte
+	mouse mouseTracker
+
+	// events are read from termbox by readEvents, so that PollEvent can be interrupted
+	// without blocking on termbox
+	events     chan Event
+	interrupts chan struct{}
+	quit       chan struct{}
+	stopped    chan struct{}
+}
+
+var _ Backend = (*TermboxBackend)(nil)
+
+func NewTermboxBackend() *TermboxBackend {
+	return &TermboxBackend{interrupts: make(chan struct{}, 1)}
+}
+
+// Init initializes termbox-go.
+func (self *TermboxBackend) Init() error {
+	if err := tb.Init(); err != nil {
+		return err
+	}
+	tb.SetInputMode(tb.InputEsc | tb.InputMouse)
+	self.initInput()
+	outputMode := self.outputMode()
+	// termbox only supports the normal output mode on Windows
+	if tb.SetOutputMode(outputMode) != outputMode {
+		self.colorMode = ColorMode16
+	}
+	self.events = make(chan Event)
+	self.quit = make(chan struct{})
+	self.stopped = make(chan struct{})
+	go self.readEvents()
+	return nil
+}
+
+// readEvents sends the events of termbox to PollEvent until Close interrupts termbox.
+func (self *TermboxBackend) readEvents() {
+	defer close(self.stopped)
+	for {
+		e := self.mouse.trackEvent(self.pollEvent())
+		if e.Type == InterruptEvent {
+			return
+		}
+		select {
+		case self.events <- e:
+		case <-self.quit:
+		}
+	}
+}
+
+// outputMode sets the ColorMode if it wasn't set with SetColorMode, and returns the matching
+// termbox output mode. Truecolor terminals use the 256 color palette unless truecolor is set.
+func (self *TermboxBackend) outputMode() tb.OutputMode {
+	if !self.colorModeSet {
+		self.colorMode = DetectColorMode()
+		if self.colorMode == ColorModeTrueColor {
+			self.colorMode = ColorMode256
+		}
+	}
+	switch self.colorMode {
+	case ColorMode16:
+		return tb.OutputNormal
+	case ColorModeTrueColor:
+		return tb.OutputRGB
+	}
+	return tb.Output256
+}
+
+// SetColorMode overrides the ColorMode detected by `DetectColorMode`, and is required to
+// send 24-bit colors. It must be called before `Init`.
+func (self *TermboxBackend) SetColorMode(mode ColorMode) {
+	self.colorMode = mode
+	self.colorModeSet = true
+}
+
+// termboxAttributes are the termbox attributes of the modifiers termbox-go is able to display.
+// Strikethrough is dropped, and double underline falls back to underline.
+var termboxAttributes = map[Modifier]tb.Attribute{
+	ModifierBold:      tb.AttrBold,
+	ModifierUnderline: tb.AttrUnderline,
+	ModifierReverse:   tb.AttrReverse,
+	ModifierItalic:    tb.AttrCursive,
+	ModifierDim:       tb.AttrDim,
+	ModifierBlink:     tb.AttrBlink,
+}
+
+// termboxModifiers are the modifiers termbox-go is able to display.
+var termboxModifiers = func() Modifier {
+	var modifiers Modifier
+	for modifier := range termboxAttributes {
+		modifiers |= modifier
+	}
+	return modifiers
+}()
+
+// color converts a Color to a termbox color attribute.
+func (self *TermboxBackend) color(color Color) tb.Attribute {
+	color = color.Downgrade(self.colorMode)
+	switch {
+	case color == ColorClear:
+		return tb.ColorDefault
+	case self.colorMode == ColorModeTrueColor:
+		return tb.RGBToAttribute(color.RGB())
+	}
+	return tb.Attribute(color + 1)
+}
+
+// attributes converts a Style to termbox foreground and background attributes.
+func (self *TermboxBackend) attributes(style Style) (tb.Attribute, tb.Attribute) {
+	modifier := style.Modifier.Fallback(termboxModifiers)
+	// in RGB mode, termbox reads the attributes of a default foreground as the color black
+	if self.colorMode == ColorModeTrueColor && style.Fg == ColorClear && modifier != ModifierClear {
+		style.Fg = ColorWhite
+		if Theme.Default.Fg != ColorClear {
+			style.Fg = Theme.Default.Fg
+		}
+	}
+	fg := self.color(style.Fg)
+	for m, attribute := range termboxAttributes {
+		if modifier&m != 0 {
+			fg |= attribute
+		}
+	}
+	return fg, self.color(style.Bg)
+}
+
+// Close closes termbox-go.
+func (self *TermboxBackend) Close() {
+	if self.quit != nil {
+		// readEvents keeps polling termbox once quit is closed, so the interrupt is received
+		close(self.quit)
+		tb.Interrupt()
+		<-self.stopped
+		self.quit = nil
+	}
+	self.closeInput()
+	tb.Close()
+}
+
+func (self *TermboxBackend) Size() (int, int) {
+	return tb.Size()
+}
+
+func (self *TermboxBackend) Sync() error {
+	return tb.Sync()
+}
+
+func (self *TermboxBackend) SetCell(x, y int, c Cell) {
+	fg, bg := self.attributes(c.Style)
+	tb.SetCell(x, y, c.Rune, fg, bg)
+}
+
+func (self *TermboxBackend) Clear(style Style) {
+	tb.Clear(self.attributes(style))
+}
+
+func (self *TermboxBackend) Flush() error {
+	return tb.Flush()
+}
+
+func (self *TermboxBackend) SetCursor(x, y int) {
+	tb.SetCursor(x, y)
+}
+
+func (self *TermboxBackend) HideCursor() {
+	tb.HideCursor()
+}
+
+// PollEvent gets an event from termbox and converts it.
+func (self *TermboxBackend) PollEvent() Event {
+	select {
+	case e := <-self.events:
+		return e
+	case <-self.interrupts:
+		return Event{
+			Type: InterruptEvent,
+			ID:   "<Interrupt>",
+		}
+	}
+}
+
+// Interrupt interrupts PollEvent.
+// Several interrupts sent while PollEvent isn't running are returned once.
+func (self *TermboxBackend) Interrupt() {
+	select {
+	case self.interrupts <- struct{}{}:
+	default:
+	}
+}
+
+var keyboardMap = map[tb.Key]Key{
+	tb.KeyF1:         {Code: KeyF1},
+	tb.KeyF2:         {Code: KeyF2},
+	tb.KeyF3:         {Code: KeyF3},
+	tb.KeyF4:         {Code: KeyF4},
+	tb.KeyF5:         {Code: KeyF5},
+	tb.KeyF6:         {Code: KeyF6},
+	tb.KeyF7:         {Code: KeyF7},
+	tb.KeyF8:         {Code: KeyF8},
+	tb.KeyF9:         {Code: KeyF9},
+	tb.KeyF10:        {Code: KeyF10},
+	tb.KeyF11:        {Code: KeyF11},
+	tb.KeyF12:        {Code: KeyF12},
+	tb.KeyInsert:     {Code: KeyInsert},
+	tb.KeyDelete:     {Code: KeyDelete},
+	tb.KeyHome:       {Code: KeyHome},
+	tb.KeyEnd:        {Code: KeyEnd},
+	tb.KeyPgup:       {Code: KeyPageUp},
+	tb.KeyPgdn:       {Code: KeyPageDown},
+	tb.KeyArrowUp:    {Code: KeyUp},
+	tb.KeyArrowDown:  {Code: KeyDown},
+	tb.KeyArrowLeft:  {Code: KeyLeft},
+	tb.KeyArrowRight: {Code: KeyRight},
+
+	tb.KeyCtrlSpace:  {Code: KeySpace, Mod: ModCtrl}, // tb.KeyCtrl2 tb.KeyCtrlTilde
+	tb.KeyCtrlA:      {Code: KeyRune, Rune: 'a', Mod: ModCtrl},
+	tb.KeyCtrlB:      {Code: KeyRune, Rune: 'b', Mod: ModCtrl},
+	tb.KeyCtrlC:      {Code: KeyRune, Rune: 'c', Mod: ModCtrl},
+	tb.KeyCtrlD:      {Code: KeyRune, Rune: 'd', Mod: ModCtrl},
+	tb.KeyCtrlE:      {Code: KeyRune, Rune: 'e', Mod: ModCtrl},
+	tb.KeyCtrlF:      {Code: KeyRune, Rune: 'f', Mod: ModCtrl},
+	tb.KeyCtrlG:      {Code: KeyRune, Rune: 'g', Mod: ModCtrl},
+	tb.KeyBackspace:  {Code: KeyBackspace, Mod: ModCtrl}, // tb.KeyCtrlH
+	tb.KeyTab:        {Code: KeyTab},                     // tb.KeyCtrlI
+	tb.KeyCtrlJ:      {Code: KeyRune, Rune: 'j', Mod: ModCtrl},
+	tb.KeyCtrlK:      {Code: KeyRune, Rune: 'k', Mod: ModCtrl},
+	tb.KeyCtrlL:      {Code: KeyRune, Rune: 'l', Mod: ModCtrl},
+	tb.KeyEnter:      {Code: KeyEnter}, // tb.KeyCtrlM
+	tb.KeyCtrlN:      {Code: KeyRune, Rune: 'n', Mod: ModCtrl},
+	tb.KeyCtrlO:      {Code: KeyRune, Rune: 'o', Mod: ModCtrl},
+	tb.KeyCtrlP:      {Code: KeyRune, Rune: 'p', Mod: ModCtrl},
+	tb.KeyCtrlQ:      {Code: KeyRune, Rune: 'q', Mod: ModCtrl},
+	tb.KeyCtrlR:      {Code: KeyRune, Rune: 'r', Mod: ModCtrl},
+	tb.KeyCtrlS:      {Code: KeyRune, Rune: 's', Mod: ModCtrl},
+	tb.KeyCtrlT:      {Code: KeyRune, Rune: 't', Mod: ModCtrl},
+	tb.KeyCtrlU:      {Code: KeyRune, Rune: 'u', Mod: ModCtrl},
+	tb.KeyCtrlV:      {Code: KeyRune, Rune: 'v', Mod: ModCtrl},
+	tb.KeyCtrlW:      {Code: KeyRune, Rune: 'w', Mod: ModCtrl},
+	tb.KeyCtrlX:      {Code: KeyRune, Rune: 'x', Mod: ModCtrl},
+	tb.KeyCtrlY:      {Code: KeyRune, Rune: 'y', Mod: ModCtrl},
+	tb.KeyCtrlZ:      {Code: KeyRune, Rune: 'z', Mod: ModCtrl},
+	tb.KeyEsc:        {Code: KeyEscape},                        // tb.KeyCtrlLsqBracket tb.KeyCtrl3
+	tb.KeyCtrl4:      {Code: KeyRune, Rune: '4', Mod: ModCtrl}, // tb.KeyCtrlBackslash
+	tb.KeyCtrl5:      {Code: KeyRune, Rune: '5', Mod: ModCtrl}, // tb.KeyCtrlRsqBracket
+	tb.KeyCtrl6:      {Code: KeyRune, Rune: '6', Mod: ModCtrl},
+	tb.KeyCtrl7:      {Code: KeyRune, Rune: '7', Mod: ModCtrl}, // tb.KeyCtrlSlash tb.KeyCtrlUnderscore
+	tb.KeySpace:      {Code: KeySpace},
+	tb.KeyBackspace2: {Code: KeyBackspace}, // tb.KeyCtrl8:
+}
+
+// convertTermboxKeyboardEvent converts a termbox keyboard event to a KeyboardEvent
+// with a Key payload and the matching string ID.
+func convertTermboxKeyboardEvent(e tb.Event) Event {
+	var key Key
+	if e.Ch != 0 {
+		key = Key{Code: KeyRune, Rune: e.Ch}
+	} else {
+		key = keyboardMap[e.Key]
+	}
+	if e.Mod == tb.ModAlt {
+		key.Mod |= ModAlt
+	}
+	return newKeyboardEvent(key)
+}
+
+var mouseButtonMap = map[tb.Key]MouseButton{
+	tb.MouseLeft:      MouseButtonLeft,
+	tb.MouseMiddle:    MouseButtonMiddle,
+	tb.MouseRight:     MouseButtonRight,
+	tb.MouseRelease:   MouseButtonNone,
+	tb.MouseWheelUp:   MouseButtonWheelUp,
+	tb.MouseWheelDown: MouseButtonWheelDown,
+}
+
+func convertTermboxMouseEvent(e tb.Event) Event {
+	m := Mouse{
+		X:      e.MouseX,
+		Y:      e.MouseY,
+		Button: mouseButtonMap[e.Key],
+	}
+	switch {
+	case e.Key == tb.MouseRelease && e.Mod == tb.ModMotion:
+		m.Action = MouseActionMove
+	case e.Key == tb.MouseRelease:
+		m.Action = MouseActionRelease
+	case e.Mod == tb.ModMotion:
+		m.Action = MouseActionDrag
+	}
+	return newMouseEvent(m)
+}
+
+// convertTermboxEvent turns a termbox event into a termui event.
+func convertTermboxEvent(e tb.Event) Event {
+	switch e.Type {
+	case tb.EventError:
+		return Event{
+			Type:    ErrorEvent,
+			ID:      "<Error>",
+			Payload: e.Err,
+		}
+	case tb.EventInterrupt:
+		return Event{
+			Type: InterruptEvent,
+			ID:   "<Interrupt>",
+		}
+	case tb.EventKey:
+		return convertTermboxKeyboardEvent(e)
+	case tb.EventMouse:
+		return convertTermboxMouseEvent(e)
+	case tb.EventResize:
+		return Event{
+			Type: ResizeEvent,
+			ID:   "<Resize>",
+			Payload: Resize{
+				Width:  e.Width,
+				Height: e.Height,
+			},
+		}
+	}
+	return Event{}
+}
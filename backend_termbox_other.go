@@ -0,0 +1,129 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build !windows
+
+package termui
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	tb "github.com/nsf/termbox-go"
+)
+
+const (
+	// enableModifyOtherKeys asks xterm compatible terminals to report keys like <C-h> or <C-i>
+	// with escape sequences, so that they can be told apart from <Backspace> or <Tab>.
+	enableModifyOtherKeys  = "\x1b[>4;2m"
+	disableModifyOtherKeys = "\x1b[>4m"
+
+	// bracketed paste makes terminals surround pasted text with pasteStart and pasteEnd.
+	enableBracketedPaste  = "\x1b[?2004h"
+	disableBracketedPaste = "\x1b[?2004l"
+	pasteStart            = "\x1b[200~"
+	pasteEnd              = "\x1b[201~"
+)
+
+// writeTerminal writes an escape sequence which termbox doesn't know about to the terminal.
+func writeTerminal(sequence string) {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer tty.Close()
+	tty.WriteString(sequence)
+}
+
+func (self *TermboxBackend) initInput() {
+	self.input = nil
+	writeTerminal(enableModifyOtherKeys + enableBracketedPaste)
+}
+
+func (self *TermboxBackend) closeInput() {
+	writeTerminal(disableModifyOtherKeys + disableBracketedPaste)
+}
+
+// pollEvent reads raw input from termbox and converts it to an event.
+// Keys combined with modifiers and mouse events are parsed by termui, everything else by termbox.
+func (self *TermboxBackend) pollEvent() Event {
+	raw := make([]byte, 256)
+	more := false
+	for {
+		if len(self.input) > 0 {
+			e, n, ok := parseTermboxInput(self.input, more)
+			self.input = self.input[n:]
+			if ok {
+				return e
+			}
+			if n > 0 {
+				continue
+			}
+		}
+
+		e := tb.PollRawEvent(raw)
+		if e.Type != tb.EventRaw {
+			return convertTermboxEvent(e)
+		}
+		self.input = append(self.input, raw[:e.N]...)
+		// a sequence may have been split if the buffer was filled
+		more = e.N == len(raw)
+	}
+}
+
+// parseTermboxInput extracts the first event of buf and returns the number of bytes it used.
+// It returns 0 if buf starts with an incomplete sequence and more input may follow,
+// which is always the case for pasted text until the end of the paste is received.
+// ok is false if the bytes didn't produce an event.
+func parseTermboxInput(buf []byte, more bool) (e Event, n int, ok bool) {
+	if bytes.HasPrefix(buf, []byte(pasteStart)) {
+		end := bytes.Index(buf, []byte(pasteEnd))
+		if end < 0 {
+			return Event{}, 0, false
+		}
+		text := string(buf[len(pasteStart):end])
+		text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(text)
+		return Event{
+			Type:    PasteEvent,
+			ID:      "<Paste>",
+			Payload: Paste{Text: text},
+		}, end + len(pasteEnd), true
+	}
+
+	key, n, partial := parseXtermKey(buf)
+	if n > 0 {
+		return newKeyboardEvent(key), n, true
+	}
+	if partial && more {
+		return Event{}, 0, false
+	}
+	mouse, n, partial := parseXtermMouse(buf)
+	if n > 0 {
+		return newMouseEvent(mouse), n, true
+	}
+	if partial && more {
+		return Event{}, 0, false
+	}
+
+	te := tb.ParseEvent(buf)
+	if te.Type == tb.EventKey && te.Key == tb.KeyEsc && te.N == 1 && len(buf) > 1 {
+		// an escape followed by a key is sent by terminals for Alt+key
+		if e, n, ok := parseTermboxInput(buf[1:], more); ok && e.Type == KeyboardEvent {
+			key := e.Payload.(Key)
+			key.Mod |= ModAlt
+			return newKeyboardEvent(key), n + 1, true
+		} else if n == 0 {
+			return Event{}, 0, false
+		}
+	}
+	if te.Type == tb.EventNone {
+		if te.N == 0 && !more {
+			// drop input which can't be parsed
+			return Event{}, 1, false
+		}
+		return Event{}, te.N, false
+	}
+	return convertTermboxEvent(te), te.N, true
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"os"
+	"testing"
+
+	tb "github.com/nsf/termbox-go"
+)
+
+func TestTermboxAttributes(t *testing.T) {
+	orange := ColorRGB(0xff, 0x88, 0x00)
+	testCases := []struct {
+		mode       ColorMode
+		style      Style
+		expectedFg tb.Attribute
+		expectedBg tb.Attribute
+	}{
+		{ColorMode256, StyleClear, tb.ColorDefault, tb.ColorDefault},
+		{ColorMode256, NewStyle(ColorRed, ColorBlack), tb.ColorRed, tb.ColorBlack},
+		{ColorMode256, NewStyle(Color(208)), 209, tb.ColorDefault},
+		{ColorMode256, NewStyle(orange), 209, tb.ColorDefault},
+		{ColorMode16, NewStyle(Color(9), Color(8)), tb.ColorLightRed, tb.ColorDarkGray},
+		{ColorMode16, NewStyle(orange), tb.ColorYellow, tb.ColorDefault},
+		{ColorModeTrueColor, NewStyle(orange, ColorClear), tb.RGBToAttribute(0xff, 0x88, 0x00), tb.ColorDefault},
+		{ColorModeTrueColor, NewStyle(ColorBlack, ColorBlue), tb.RGBToAttribute(0, 0, 0), tb.RGBToAttribute(0, 0, 238)},
+		{ColorMode256, NewStyle(ColorClear, ColorClear, ModifierBold|ModifierUnderline), tb.AttrBold | tb.AttrUnderline, tb.ColorDefault},
+		{ColorMode256, NewStyle(ColorClear, ColorClear, ModifierReverse|ModifierBlink), tb.AttrReverse | tb.AttrBlink, tb.ColorDefault},
+		{ColorMode256, NewStyle(ColorClear, ColorClear, ModifierItalic|ModifierDim), tb.AttrCursive | tb.AttrDim, tb.ColorDefault},
+		{ColorMode256, NewStyle(ColorClear, ColorClear, ModifierDoubleUnderline), tb.AttrUnderline, tb.ColorDefault},
+		{ColorMode256, NewStyle(ColorClear, ColorClear, ModifierStrikethrough|ModifierBold), tb.AttrBold, tb.ColorDefault},
+		{ColorModeTrueColor, NewStyle(ColorClear, ColorClear, ModifierBold), tb.RGBToAttribute(229, 229, 229) | tb.AttrBold, tb.ColorDefault},
+		{ColorModeTrueColor, NewStyle(ColorClear, ColorRed, ModifierStrikethrough), tb.ColorDefault, tb.RGBToAttribute(205, 0, 0)},
+		{ColorModeTrueColor, NewStyle(orange, ColorClear, ModifierUnderline|ModifierItalic), tb.RGBToAttribute(0xff, 0x88, 0x00) | tb.AttrUnderline | tb.AttrCursive, tb.ColorDefault},
+		{ColorModeTrueColor, NewStyle(ColorRed, ColorClear, ModifierReverse), tb.RGBToAttribute(205, 0, 0) | tb.AttrReverse, tb.ColorDefault},
+	}
+	for _, tc := range testCases {
+		backend := &TermboxBackend{colorMode: tc.mode}
+		fg, bg := backend.attributes(tc.style)
+		if fg != tc.expectedFg || bg != tc.expectedBg {
+			t.Errorf("%v in mode %d: expected %x, %x, got %x, %x", tc.style, tc.mode, tc.expectedFg, tc.expectedBg, fg, bg)
+		}
+	}
+}
+
+func TestTermboxOutputMode(t *testing.T) {
+	defer os.Setenv("COLORTERM", os.Getenv("COLORTERM"))
+	os.Setenv("COLORTERM", "truecolor")
+
+	backend := NewTermboxBackend()
+	if mode := backend.outputMode(); mode != tb.Output256 || backend.colorMode != ColorMode256 {
+		t.Errorf("expected truecolor terminals to use 256 colors by default, got %v, %d", mode, backend.colorMode)
+	}
+
+	backend = NewTermboxBackend()
+	backend.SetColorMode(ColorModeTrueColor)
+	if mode := backend.outputMode(); mode != tb.OutputRGB || backend.colorMode != ColorModeTrueColor {
+		t.Errorf("expected RGB output once truecolor is set, got %v, %d", mode, backend.colorMode)
+	}
+
+	backend = NewTermboxBackend()
+	backend.SetColorMode(ColorMode16)
+	if mode := backend.outputMode(); mode != tb.OutputNormal {
+		t.Errorf("expected the normal output for 16 colors, got %v", mode)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+// +build windows
+
+package termui
+
+import (
+	tb "github.com/nsf/termbox-go"
+)
+
+func (self *TermboxBackend) initInput() {}
+
+func (self *TermboxBackend) closeInput() {}
+
+func (self *TermboxBackend) pollEvent() Event {
+	return convertTermboxEvent(tb.PollEvent())
+}
@@ -16,16 +16,42 @@ import (
 type Block struct {
 	Border      bool
 	BorderStyle Style
+	// BorderSideStyles overrides BorderStyle for some sides of the border.
+	// The corners take the style of the top and bottom sides.
+	BorderSideStyles map[BorderSide]Style
+	// BorderSet holds the runes of the border, the zero BorderSet draws BorderSetSingle.
+	BorderSet BorderSet
+	// FocusedBorderStyle replaces BorderStyle while the block is focused.
+	FocusedBorderStyle Style
+	focused            bool
 
 	BorderLeft, BorderRight, BorderTop, BorderBottom bool
 
 	PaddingLeft, PaddingRight, PaddingTop, PaddingBottom int
+	// Margins are left empty around the border, inside the rect of the block.
+	MarginLeft, MarginRight, MarginTop, MarginBottom int
+
+	// Background fills the block inside its margins before the border and content are drawn,
+	// clearing what was drawn below. If its background color is ColorClear, the one below is kept,
+	// so that a block inherits the background of the container it is drawn in.
+	// Blocks are transparent if Background is StyleClear or the zero Style.
+	Background Style
+	// Shadow draws a drop shadow below and to the right of the block, for floating panels.
+	// The shadow takes the last column and row of the rect of the block.
+	Shadow      bool
+	ShadowStyle Style
 
 	image.Rectangle
+	// Inner is the rectangle in which the content is drawn, inside the margins, shadow,
+	// border and padding. A side without border still leaves a row for the titles drawn on it.
 	Inner image.Rectangle
 
-	Title      string
-	TitleStyle Style
+	// Title is drawn on the top border, and parsed with ParseStyles.
+	Title          string
+	TitleStyle     Style
+	TitleAlignment Alignment
+	// Titles are drawn on the top or bottom border in addition to Title.
+	Titles []BlockTitle
 
 	sync.Mutex
 }
@@ -34,69 +60,193 @@ func NewBlock() *Block {
 	return &Block{
 		Border:       true,
 		BorderStyle:  Theme.Block.Border,
+		BorderSet:    Theme.Block.BorderSet,
 		BorderLeft:   true,
 		BorderRight:  true,
 		BorderTop:    true,
 		BorderBottom: true,
 
+		FocusedBorderStyle: Theme.Block.FocusedBorder,
+
+		Background:  Theme.Block.Background,
+		ShadowStyle: Theme.Block.Shadow,
+
 		TitleStyle: Theme.Block.Title,
 	}
 }
 
+// frame returns the rectangle in which the background and border are drawn,
+// which is the rect of the block without its margins and shadow.
+func (self *Block) frame() image.Rectangle {
+	insets := image.Rectangle{
+		Min: image.Pt(self.MarginLeft, self.MarginTop),
+		Max: image.Pt(self.MarginRight, self.MarginBottom),
+	}
+	if self.Shadow {
+		insets.Max = insets.Max.Add(image.Pt(1, 1))
+	}
+	return inset(self.Rectangle, insets)
+}
+
+// inset returns rect shrunk by the insets, Min holding the left and top ones and Max
+// the right and bottom ones. The result is empty if the insets are larger than rect.
+func inset(rect image.Rectangle, insets image.Rectangle) image.Rectangle {
+	// not image.Rect, which would swap the sides of a rect smaller than its insets
+	min := rect.Min.Add(insets.Min)
+	max := rect.Max.Sub(insets.Max)
+	return image.Rectangle{
+		Min: min,
+		Max: image.Pt(MaxInt(max.X, min.X), MaxInt(max.Y, min.Y)),
+	}
+}
+
+// insets returns the number of cells between the rect of the block and Inner on each side,
+// Min holding the left and top ones and Max the right and bottom ones.
+func (self *Block) insets() image.Rectangle {
+	side := func(border bool, titles bool) int {
+		if (self.Border && border) || titles {
+			return 1
+		}
+		return 0
+	}
+	hasTitles := func(bottom bool) bool {
+		for _, group := range self.titleRow(bottom) {
+			if len(group) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	// not image.Rect, which would swap the sides of unbalanced insets
+	insets := image.Rectangle{
+		Min: image.Pt(
+			self.MarginLeft+side(self.BorderLeft, false)+self.PaddingLeft,
+			self.MarginTop+side(self.BorderTop, hasTitles(false))+self.PaddingTop,
+		),
+		Max: image.Pt(
+			self.MarginRight+side(self.BorderRight, false)+self.PaddingRight,
+			self.MarginBottom+side(self.BorderBottom, hasTitles(true))+self.PaddingBottom,
+		),
+	}
+	if self.Shadow {
+		insets.Max = insets.Max.Add(image.Pt(1, 1))
+	}
+	return insets
+}
+
+// outside returns the number of columns and rows taken by the margins and shadow.
+func (self *Block) outside() image.Point {
+	size := image.Pt(self.MarginLeft+self.MarginRight, self.MarginTop+self.MarginBottom)
+	if self.Shadow {
+		size = size.Add(image.Pt(1, 1))
+	}
+	return size
+}
+
 func (self *Block) drawBorder(buf *Buffer) {
-	verticalCell := Cell{VERTICAL_LINE, self.BorderStyle}
-	horizontalCell := Cell{HORIZONTAL_LINE, self.BorderStyle}
+	set := self.BorderSet.orDefault()
+	frame := self.frame()
+	top, bottom := self.borderStyle(BorderSideTop), self.borderStyle(BorderSideBottom)
+	left, right := self.borderStyle(BorderSideLeft), self.borderStyle(BorderSideRight)
 
 	// draw lines
 	if self.BorderTop {
-		buf.Fill(horizontalCell, image.Rect(self.Min.X, self.Min.Y, self.Max.X, self.Min.Y+1))
+		buf.Fill(Cell{set.Horizontal, top}, image.Rect(frame.Min.X, frame.Min.Y, frame.Max.X, frame.Min.Y+1))
 	}
 	if self.BorderBottom {
-		buf.Fill(horizontalCell, image.Rect(self.Min.X, self.Max.Y-1, self.Max.X, self.Max.Y))
+		buf.Fill(Cell{set.Horizontal, bottom}, image.Rect(frame.Min.X, frame.Max.Y-1, frame.Max.X, frame.Max.Y))
 	}
 	if self.BorderLeft {
-		buf.Fill(verticalCell, image.Rect(self.Min.X, self.Min.Y, self.Min.X+1, self.Max.Y))
+		buf.Fill(Cell{set.Vertical, left}, image.Rect(frame.Min.X, frame.Min.Y, frame.Min.X+1, frame.Max.Y))
 	}
 	if self.BorderRight {
-		buf.Fill(verticalCell, image.Rect(self.Max.X-1, self.Min.Y, self.Max.X, self.Max.Y))
+		buf.Fill(Cell{set.Vertical, right}, image.Rect(frame.Max.X-1, frame.Min.Y, frame.Max.X, frame.Max.Y))
 	}
 
 	// draw corners
 	if self.BorderTop && self.BorderLeft {
-		buf.SetCell(Cell{TOP_LEFT, self.BorderStyle}, self.Min)
+		buf.SetCell(Cell{set.TopLeft, top}, frame.Min)
 	}
 	if self.BorderTop && self.BorderRight {
-		buf.SetCell(Cell{TOP_RIGHT, self.BorderStyle}, image.Pt(self.Max.X-1, self.Min.Y))
+		buf.SetCell(Cell{set.TopRight, top}, image.Pt(frame.Max.X-1, frame.Min.Y))
 	}
 	if self.BorderBottom && self.BorderLeft {
-		buf.SetCell(Cell{BOTTOM_LEFT, self.BorderStyle}, image.Pt(self.Min.X, self.Max.Y-1))
+		buf.SetCell(Cell{set.BottomLeft, bottom}, image.Pt(frame.Min.X, frame.Max.Y-1))
 	}
 	if self.BorderBottom && self.BorderRight {
-		buf.SetCell(Cell{BOTTOM_RIGHT, self.BorderStyle}, self.Max.Sub(image.Pt(1, 1)))
+		buf.SetCell(Cell{set.BottomRight, bottom}, frame.Max.Sub(image.Pt(1, 1)))
+	}
+}
+
+// drawBackground fills the frame with the Background.
+func (self *Block) drawBackground(buf *Buffer) {
+	rect := self.frame().Intersect(buf.Rectangle)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			point := image.Pt(x, y)
+			style := self.Background
+			if style.Bg == ColorClear {
+				style.Bg = buf.GetCell(point).Style.Bg
+			}
+			buf.SetCell(Cell{' ', style}, point)
+		}
+	}
+}
+
+// drawShadow shades the cells below and to the right of the frame with ShadowStyle.
+func (self *Block) drawShadow(buf *Buffer) {
+	frame := self.frame()
+	shadow := []image.Rectangle{
+		image.Rect(frame.Max.X, frame.Min.Y+1, frame.Max.X+1, frame.Max.Y+1),
+		image.Rect(frame.Min.X+1, frame.Max.Y, frame.Max.X, frame.Max.Y+1),
+	}
+	for _, rect := range shadow {
+		rect = rect.Intersect(buf.Rectangle)
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				point := image.Pt(x, y)
+				cell := buf.GetCell(point)
+				cell.Style = cell.Style.Overlay(self.ShadowStyle)
+				buf.SetCell(cell, point)
+			}
+		}
 	}
 }
 
 // Draw implements the Drawable interface.
+// It fills the background, then draws the shadow, border and titles, clipped to the rect of the block.
 func (self *Block) Draw(buf *Buffer) {
+	buf = buf.Sub(self.Rectangle)
+	if self.frame().Empty() {
+		return
+	}
+	if self.Background != StyleClear && self.Background != (Style{}) {
+		self.drawBackground(buf)
+	}
+	if self.Shadow {
+		self.drawShadow(buf)
+	}
 	if self.Border {
 		self.drawBorder(buf)
 	}
-	buf.SetString(
-		self.Title,
-		self.TitleStyle,
-		image.Pt(self.Min.X+2, self.Min.Y),
-	)
+	self.drawTitles(buf, false)
+	self.drawTitles(buf, true)
 }
 
 // SetRect implements the Drawable interface.
 func (self *Block) SetRect(x1, y1, x2, y2 int) {
 	self.Rectangle = image.Rect(x1, y1, x2, y2)
-	self.Inner = image.Rect(
-		self.Min.X+1+self.PaddingLeft,
-		self.Min.Y+1+self.PaddingTop,
-		self.Max.X-1-self.PaddingRight,
-		self.Max.Y-1-self.PaddingBottom,
-	)
+	self.Inner = inset(self.Rectangle, self.insets())
+}
+
+// SetFocused is called by FocusManager when the block gains or loses the focus.
+func (self *Block) SetFocused(focused bool) {
+	self.focused = focused
+}
+
+// Focused reports whether the block has the focus.
+func (self *Block) Focused() bool {
+	return self.focused
 }
 
 // GetRect implements the Drawable interface.
@@ -0,0 +1,104 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestBlockInner(t *testing.T) {
+	testCases := []struct {
+		name     string
+		setup    func(*Block)
+		rect     image.Rectangle
+		expected image.Rectangle
+	}{
+		{"border", func(b *Block) {}, image.Rect(0, 0, 10, 5), image.Rect(1, 1, 9, 4)},
+		{"no border", func(b *Block) { b.Border = false }, image.Rect(0, 0, 10, 5), image.Rect(0, 0, 10, 5)},
+		{"no top border", func(b *Block) { b.BorderTop = false }, image.Rect(0, 0, 10, 5), image.Rect(1, 0, 9, 4)},
+		{"title without border", func(b *Block) {
+			b.Border = false
+			b.Title = "title"
+		}, image.Rect(0, 0, 10, 5), image.Rect(0, 1, 10, 5)},
+		{"bottom title without border", func(b *Block) {
+			b.Border = false
+			b.Titles = []BlockTitle{{Text: "title", Bottom: true}}
+		}, image.Rect(0, 0, 10, 5), image.Rect(0, 0, 10, 4)},
+		{"padding", func(b *Block) {
+			b.PaddingLeft, b.PaddingTop = 2, 1
+		}, image.Rect(0, 0, 10, 5), image.Rect(3, 2, 9, 4)},
+		{"margins and shadow", func(b *Block) {
+			b.MarginLeft, b.MarginRight, b.MarginTop, b.MarginBottom = 1, 2, 1, 0
+			b.Shadow = true
+		}, image.Rect(0, 0, 10, 5), image.Rect(2, 2, 6, 3)},
+		{"smaller than its border", func(b *Block) {}, image.Rect(0, 0, 1, 1), image.Rect(1, 1, 1, 1)},
+		{"smaller than its margins", func(b *Block) {
+			b.MarginLeft, b.MarginRight = 4, 4
+		}, image.Rect(0, 0, 5, 5), image.Rect(5, 1, 5, 4)},
+	}
+	for _, tc := range testCases {
+		block := NewBlock()
+		tc.setup(block)
+		block.SetRect(tc.rect.Min.X, tc.rect.Min.Y, tc.rect.Max.X, tc.rect.Max.Y)
+		if block.Inner != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, block.Inner)
+		}
+	}
+}
+
+func TestBlockDraw(t *testing.T) {
+	block := NewBlock()
+	block.MarginLeft = 1
+	block.Shadow = true
+	block.Background = NewStyle(ColorWhite, ColorBlue)
+	block.SetRect(0, 0, 6, 4)
+	buf := NewBuffer(image.Rect(0, 0, 7, 5))
+	buf.Fill(Cell{'x', StyleClear}, buf.Rectangle)
+	block.Draw(buf)
+
+	expected := []string{
+		"x┌──┐xx",
+		"x│  │xx",
+		"x└──┘xx",
+		"xxxxxxx",
+		"xxxxxxx",
+	}
+	for y, line := range expected {
+		x := 0
+		for _, r := range line {
+			if cell := buf.GetCell(image.Pt(x, y)); cell.Rune != r {
+				t.Errorf("%d, %d: expected %q, got %q", x, y, r, cell.Rune)
+			}
+			x++
+		}
+	}
+	if cell := buf.GetCell(image.Pt(2, 1)); cell.Style.Bg != ColorBlue {
+		t.Errorf("expected the background to be filled, got %v", cell.Style)
+	}
+	for _, p := range []image.Point{image.Pt(5, 1), image.Pt(5, 3), image.Pt(2, 3)} {
+		if cell := buf.GetCell(p); cell.Rune != 'x' || cell.Style.Bg != block.ShadowStyle.Bg {
+			t.Errorf("%v: expected a shadow, got %v", p, cell)
+		}
+	}
+	for _, p := range []image.Point{image.Pt(5, 0), image.Pt(1, 3), image.Pt(6, 1)} {
+		if cell := buf.GetCell(p); cell.Style != StyleClear {
+			t.Errorf("%v: expected no shadow, got %v", p, cell)
+		}
+	}
+}
+
+func TestBlockTransparentBackground(t *testing.T) {
+	for _, background := range []Style{StyleClear, {}} {
+		block := &Block{Background: background}
+		block.SetRect(0, 0, 3, 3)
+		buf := NewBuffer(block.Rectangle)
+		buf.Fill(Cell{'x', NewStyle(ColorRed, ColorGreen)}, buf.Rectangle)
+		block.Draw(buf)
+		if cell := buf.GetCell(image.Pt(1, 1)); cell != (Cell{'x', NewStyle(ColorRed, ColorGreen)}) {
+			t.Errorf("%v: expected the cells below the block to be kept, got %v", background, cell)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// BorderSet holds the runes used to draw the border of a Block, including the junctions
+// drawn where the borders of adjacent blocks meet in a Grid with CollapseBorders.
+type BorderSet struct {
+	Horizontal, Vertical                       rune
+	TopLeft, TopRight, BottomLeft, BottomRight rune
+	VerticalLeft, VerticalRight                rune
+	HorizontalUp, HorizontalDown               rune
+	Cross                                      rune
+}
+
+var (
+	// BorderSetSingle uses the line symbols of the platform, which are ASCII on Windows.
+	BorderSetSingle = BorderSet{
+		HORIZONTAL_LINE, VERTICAL_LINE,
+		TOP_LEFT, TOP_RIGHT, BOTTOM_LEFT, BOTTOM_RIGHT,
+		VERTICAL_LEFT, VERTICAL_RIGHT,
+		HORIZONTAL_UP, HORIZONTAL_DOWN,
+		CROSS,
+	}
+	BorderSetRounded = BorderSet{
+		'─', '│',
+		'╭', '╮', '╰', '╯',
+		'┤', '├',
+		'┴', '┬',
+		'┼',
+	}
+	BorderSetDouble = BorderSet{
+		'═', '║',
+		'╔', '╗', '╚', '╝',
+		'╣', '╠',
+		'╩', '╦',
+		'╬',
+	}
+	BorderSetHeavy = BorderSet{
+		'━', '┃',
+		'┏', '┓', '┗', '┛',
+		'┫', '┣',
+		'┻', '┳',
+		'╋',
+	}
+	BorderSetDashed = BorderSet{
+		HORIZONTAL_DASH, VERTICAL_DASH,
+		TOP_LEFT, TOP_RIGHT, BOTTOM_LEFT, BOTTOM_RIGHT,
+		VERTICAL_LEFT, VERTICAL_RIGHT,
+		HORIZONTAL_UP, HORIZONTAL_DOWN,
+		CROSS,
+	}
+	// BorderSetASCII only uses ASCII characters, for legacy terminals.
+	BorderSetASCII = BorderSet{
+		'-', '|',
+		'+', '+', '+', '+',
+		'+', '+',
+		'+', '+',
+		'+',
+	}
+)
+
+// BorderSide is a side of the border of a Block.
+type BorderSide uint
+
+const (
+	BorderSideTop BorderSide = iota
+	BorderSideBottom
+	BorderSideLeft
+	BorderSideRight
+)
+
+// borderLines is a set of the directions in which the lines of a border leave a cell.
+type borderLines uint8
+
+const (
+	lineUp borderLines = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+// orDefault returns BorderSetSingle for the zero BorderSet, e.g. of a Block which
+// wasn't created by NewBlock, and the set otherwise.
+func (self BorderSet) orDefault() BorderSet {
+	if self == (BorderSet{}) {
+		return BorderSetSingle
+	}
+	return self
+}
+
+// rune returns the rune of the set joining the lines.
+func (self BorderSet) rune(lines borderLines) rune {
+	switch lines {
+	case lineLeft | lineRight, lineLeft, lineRight:
+		return self.Horizontal
+	case lineUp | lineDown, lineUp, lineDown:
+		return self.Vertical
+	case lineDown | lineRight:
+		return self.TopLeft
+	case lineDown | lineLeft:
+		return self.TopRight
+	case lineUp | lineRight:
+		return self.BottomLeft
+	case lineUp | lineLeft:
+		return self.BottomRight
+	case lineUp | lineDown | lineLeft:
+		return self.VerticalLeft
+	case lineUp | lineDown | lineRight:
+		return self.VerticalRight
+	case lineLeft | lineRight | lineUp:
+		return self.HorizontalUp
+	case lineLeft | lineRight | lineDown:
+		return self.HorizontalDown
+	}
+	return self.Cross
+}
+
+// has reports whether the rune is one of the runes of the set.
+func (self BorderSet) has(r rune) bool {
+	for _, other := range [...]rune{
+		self.Horizontal, self.Vertical,
+		self.TopLeft, self.TopRight, self.BottomLeft, self.BottomRight,
+		self.VerticalLeft, self.VerticalRight,
+		self.HorizontalUp, self.HorizontalDown,
+		self.Cross,
+	} {
+		if r == other {
+			return true
+		}
+	}
+	return false
+}
+
+// borderLines returns the directions in which the border of the block leaves the point,
+// which is 0 if the point isn't on the border, and the runes of the border.
+func (self *Block) borderLines(p image.Point) (borderLines, BorderSet) {
+	set := self.BorderSet.orDefault()
+	frame := self.frame()
+	if !self.Border || !p.In(frame) {
+		return 0, set
+	}
+	top := self.BorderTop && p.Y == frame.Min.Y
+	bottom := self.BorderBottom && p.Y == frame.Max.Y-1
+	left := self.BorderLeft && p.X == frame.Min.X
+	right := self.BorderRight && p.X == frame.Max.X-1
+
+	var lines borderLines
+	if top || bottom {
+		if p.X > frame.Min.X {
+			lines |= lineLeft
+		}
+		if p.X < frame.Max.X-1 {
+			lines |= lineRight
+		}
+	}
+	if left || right {
+		if p.Y > frame.Min.Y {
+			lines |= lineUp
+		}
+		if p.Y < frame.Max.Y-1 {
+			lines |= lineDown
+		}
+	}
+	return lines, set
+}
+
+// borderStyle returns the style of a side of the border.
+func (self *Block) borderStyle(side BorderSide) Style {
+	if self.focused {
+		return self.FocusedBorderStyle
+	}
+	if style, ok := self.BorderSideStyles[side]; ok {
+		return style
+	}
+	return self.BorderStyle
+}
+
+// bordered is implemented by the widgets embedding a Block.
+type bordered interface {
+	borderLines(image.Point) (borderLines, BorderSet)
+}
+
+// mergeBorders replaces the border runes of the cells on the borders of several items by the junction
+// of all their lines, e.g. ┼ where the corners of four blocks meet. Titles drawn over a border are kept.
+func mergeBorders(buf *Buffer, items []Drawable) {
+	blocks := []bordered{}
+	for _, item := range items {
+		if block, ok := item.(bordered); ok {
+			blocks = append(blocks, block)
+		}
+	}
+	rect := buf.Rectangle
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			point := image.Pt(x, y)
+			var lines borderLines
+			var set BorderSet
+			shared := 0
+			for _, block := range blocks {
+				if blockLines, blockSet := block.borderLines(point); blockLines != 0 {
+					lines |= blockLines
+					set = blockSet
+					shared++
+				}
+			}
+			if cell := buf.GetCell(point); shared > 1 && set.has(cell.Rune) {
+				cell.Rune = set.rune(lines)
+				buf.SetCell(cell, point)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func TestBorderSets(t *testing.T) {
+	testCases := []struct {
+		name string
+		set  BorderSet
+	}{
+		{"single", BorderSetSingle},
+		{"rounded", BorderSetRounded},
+		{"double", BorderSetDouble},
+		{"heavy", BorderSetHeavy},
+		{"dashed", BorderSetDashed},
+		{"ascii", BorderSetASCII},
+	}
+	for _, tc := range testCases {
+		block := NewBlock()
+		block.Title = tc.name
+		block.BorderSet = tc.set
+		termuitest.AssertGolden(t, "border_"+tc.name, block, 10, 3)
+	}
+}
+
+func TestBorderSetZeroValue(t *testing.T) {
+	block := &Block{
+		Border:       true,
+		BorderLeft:   true,
+		BorderRight:  true,
+		BorderTop:    true,
+		BorderBottom: true,
+	}
+	termuitest.AssertGolden(t, "border_zero", block, 6, 3)
+}
+
+func TestBorderSideStyles(t *testing.T) {
+	block := NewBlock()
+	block.BorderSideStyles = map[BorderSide]Style{
+		BorderSideTop:  NewStyle(ColorRed),
+		BorderSideLeft: NewStyle(ColorGreen),
+	}
+	block.BorderLeft = false
+	termuitest.AssertStyledGolden(t, "border_sides", block, 6, 3)
+}
+
+// newCollapsedGrid returns a Grid of rows x cols blocks sharing their borders.
+func newCollapsedGrid(set BorderSet, rows, cols int) *Grid {
+	grid := NewGrid()
+	grid.CollapseBorders = true
+	items := []interface{}{}
+	for i := 0; i < rows; i++ {
+		row := []interface{}{}
+		for j := 0; j < cols; j++ {
+			block := NewBlock()
+			block.BorderSet = set
+			row = append(row, NewCol(1/float64(cols), block))
+		}
+		items = append(items, NewRow(1/float64(rows), row...))
+	}
+	grid.Set(items...)
+	return grid
+}
+
+func TestGridCollapseBorders(t *testing.T) {
+	termuitest.AssertGolden(t, "grid_collapsed", newCollapsedGrid(BorderSetSingle, 2, 2), 9, 5)
+	termuitest.AssertGolden(t, "grid_collapsed_rounded", newCollapsedGrid(BorderSetRounded, 2, 3), 13, 5)
+	termuitest.AssertGolden(t, "grid_collapsed_double", newCollapsedGrid(BorderSetDouble, 3, 1), 6, 7)
+}
+
+func TestGridCollapseBordersKeepsTitles(t *testing.T) {
+	grid := newCollapsedGrid(BorderSetSingle, 2, 2)
+	for i, item := range grid.Items {
+		item.Entry.(*Block).Title = string(rune('a' + i))
+	}
+	termuitest.AssertGolden(t, "grid_collapsed_titles", grid, 9, 5)
+}
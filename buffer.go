@@ -36,32 +36,84 @@ func NewCell(rune rune, args ...interface{}) Cell {
 }
 
 // Buffer represents a section of a terminal and is a renderable rectangle of cells.
+// Cells are stored in a contiguous slice in row-major order.
+// Points outside of the Buffer's rectangle are ignored by SetCell and Fill.
+//
+// A Buffer can also be a view into the cells of another Buffer, returned by Sub or Local.
 type Buffer struct {
 	image.Rectangle
-	CellMap map[image.Point]Cell
+	cells []Cell
+	// bounds is the rectangle of the cells slice, in the coordinates of the Buffer
+	// which allocated it.
+	bounds image.Rectangle
+	// offset translates the coordinates of the Buffer to the ones of bounds.
+	offset image.Point
 }
 
 func NewBuffer(r image.Rectangle) *Buffer {
+	r = r.Canon()
 	buf := &Buffer{
 		Rectangle: r,
-		CellMap:   make(map[image.Point]Cell),
+		cells:     make([]Cell, r.Dx()*r.Dy()),
+		bounds:    r,
+	}
+	for i := range buf.cells { // clears out area
+		buf.cells[i] = CellClear
 	}
-	buf.Fill(CellClear, r) // clears out area
 	return buf
 }
 
+// index returns the position of p in the cells slice. p must be inside the Buffer.
+func (self *Buffer) index(p image.Point) int {
+	p = p.Add(self.offset)
+	return (p.Y-self.bounds.Min.Y)*self.bounds.Dx() + p.X - self.bounds.Min.X
+}
+
+// Sub returns a view of the cells of the Buffer inside rect, sharing them with the Buffer.
+// Drawing into the view is clipped to rect, so a widget given a Sub buffer can't draw over
+// its neighbours.
+func (self *Buffer) Sub(rect image.Rectangle) *Buffer {
+	return &Buffer{
+		Rectangle: rect.Canon().Intersect(self.Rectangle),
+		cells:     self.cells,
+		bounds:    self.bounds,
+		offset:    self.offset,
+	}
+}
+
+// Local returns a view of the cells of the Buffer in which the top left corner
+// of the Buffer is at 0, 0.
+func (self *Buffer) Local() *Buffer {
+	return &Buffer{
+		Rectangle: self.Rectangle.Sub(self.Min),
+		cells:     self.cells,
+		bounds:    self.bounds,
+		offset:    self.offset.Add(self.Min),
+	}
+}
+
+// GetCell returns the Cell at p, or the zero Cell if p is outside of the Buffer.
 func (self *Buffer) GetCell(p image.Point) Cell {
-	return self.CellMap[p]
+	if !p.In(self.Rectangle) {
+		return Cell{}
+	}
+	return self.cells[self.index(p)]
 }
 
 func (self *Buffer) SetCell(c Cell, p image.Point) {
-	self.CellMap[p] = c
+	if !p.In(self.Rectangle) {
+		return
+	}
+	self.cells[self.index(p)] = c
 }
 
 func (self *Buffer) Fill(c Cell, rect image.Rectangle) {
-	for x := rect.Min.X; x < rect.Max.X; x++ {
-		for y := rect.Min.Y; y < rect.Max.Y; y++ {
-			self.SetCell(c, image.Pt(x, y))
+	rect = rect.Canon().Intersect(self.Rectangle)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		start := self.index(image.Pt(rect.Min.X, y))
+		row := self.cells[start : start+rect.Dx()]
+		for i := range row {
+			row[i] = c
 		}
 	}
 }
@@ -74,3 +126,17 @@ func (self *Buffer) SetString(s string, style Style, p image.Point) {
 		x += rw.RuneWidth(char)
 	}
 }
+
+// CellMap returns the cells of the Buffer indexed by their position.
+// It is provided for compatibility with the map based Buffer of previous versions
+// and is slow, prefer GetCell.
+func (self *Buffer) CellMap() map[image.Point]Cell {
+	cellMap := make(map[image.Point]Cell, len(self.cells))
+	for y := self.Min.Y; y < self.Max.Y; y++ {
+		for x := self.Min.X; x < self.Max.X; x++ {
+			p := image.Pt(x, y)
+			cellMap[p] = self.cells[self.index(p)]
+		}
+	}
+	return cellMap
+}
@@ -0,0 +1,196 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bufferText returns the runes of the rect of the Buffer's cells, one line per row,
+// with '.' for the points outside of the Buffer.
+func bufferText(buf *Buffer, rect image.Rectangle) string {
+	lines := []string{}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		var sb strings.Builder
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			cell := buf.GetCell(image.Pt(x, y))
+			if cell.Rune == 0 {
+				cell.Rune = '.'
+			}
+			sb.WriteRune(cell.Rune)
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func expectText(t *testing.T, buf *Buffer, rect image.Rectangle, lines ...string) {
+	t.Helper()
+	expected := strings.Join(lines, "\n")
+	if text := bufferText(buf, rect); text != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, text)
+	}
+}
+
+func TestBufferOrigin(t *testing.T) {
+	// the rectangle is made canonical and doesn't start at 0, 0
+	buf := NewBuffer(image.Rect(5, 4, 2, 2))
+	if buf.Rectangle != image.Rect(2, 2, 5, 4) {
+		t.Fatalf("expected %v, got %v", image.Rect(2, 2, 5, 4), buf.Rectangle)
+	}
+	if cell := buf.GetCell(image.Pt(2, 2)); cell != CellClear {
+		t.Errorf("expected a clear cell, got %+v", cell)
+	}
+	buf.SetCell(NewCell('a'), image.Pt(2, 2))
+	buf.SetCell(NewCell('b'), image.Pt(4, 3))
+	buf.SetCell(NewCell('x'), image.Pt(5, 3))
+	buf.SetCell(NewCell('x'), image.Pt(1, 2))
+	buf.SetCell(NewCell('x'), image.Pt(0, 0))
+	expectText(t, buf, image.Rect(1, 1, 6, 5),
+		".....",
+		".a  .",
+		".  b.",
+		".....",
+	)
+	if cell := buf.GetCell(image.Pt(5, 3)); cell != (Cell{}) {
+		t.Errorf("expected the zero cell outside of the buffer, got %+v", cell)
+	}
+}
+
+func TestBufferFillClipping(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 4, 3))
+	buf.Fill(NewCell('a'), image.Rect(2, 1, 10, 10))
+	buf.Fill(NewCell('b'), image.Rect(1, 1, -5, -5))
+	buf.Fill(NewCell('x'), image.Rect(4, 0, 8, 3))
+	buf.Fill(NewCell('x'), image.Rect(-3, -3, -1, -1))
+	expectText(t, buf, buf.Rectangle,
+		"b   ",
+		"  aa",
+		"  aa",
+	)
+}
+
+func TestBufferSetStringClipping(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 5, 1))
+	buf.SetString("世界!", StyleClear, image.Pt(0, 0))
+	if cell := buf.GetCell(image.Pt(2, 0)); cell.Rune != '界' {
+		t.Errorf("expected wide runes to take two cells, got %q", cell.Rune)
+	}
+	buf.SetString("abc", StyleClear, image.Pt(-1, 0))
+	expectText(t, buf, image.Rect(0, 0, 6, 1), "bc界 !.")
+}
+
+func TestBufferSub(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 6, 4))
+	sub := buf.Sub(image.Rect(1, 1, 4, 3))
+
+	sub.Fill(NewCell('a'), image.Rect(0, 0, 10, 10))
+	sub.SetCell(NewCell('b'), image.Pt(3, 1))
+	sub.SetCell(NewCell('c'), image.Pt(4, 2))
+	sub.SetCell(NewCell('c'), image.Pt(1, 3))
+	expectText(t, buf, buf.Rectangle,
+		"      ",
+		" aab  ",
+		" aaa  ",
+		"      ",
+	)
+	if cell := sub.GetCell(image.Pt(0, 0)); cell != (Cell{}) {
+		t.Errorf("expected the zero cell outside of the view, got %+v", cell)
+	}
+
+	// writes to the Buffer are visible in the view
+	buf.SetCell(NewCell('d'), image.Pt(2, 2))
+	if cell := sub.GetCell(image.Pt(2, 2)); cell.Rune != 'd' {
+		t.Errorf("expected the view to share the cells of the buffer, got %q", cell.Rune)
+	}
+}
+
+func TestBufferNestedSub(t *testing.T) {
+	buf := NewBuffer(image.Rect(0, 0, 6, 4))
+	outer := buf.Sub(image.Rect(1, 0, 5, 3))
+	inner := outer.Sub(image.Rect(3, 1, 8, 8))
+	if inner.Rectangle != image.Rect(3, 1, 5, 3) {
+		t.Fatalf("expected the view to be clipped to its parent, got %v", inner.Rectangle)
+	}
+	inner.Fill(NewCell('a'), buf.Rectangle)
+	expectText(t, buf, buf.Rectangle,
+		"      ",
+		"   aa ",
+		"   aa ",
+		"      ",
+	)
+
+	// a view outside of its parent is empty
+	empty := outer.Sub(image.Rect(5, 0, 6, 4))
+	if !empty.Empty() {
+		t.Errorf("expected an empty view, got %v", empty.Rectangle)
+	}
+	empty.Fill(NewCell('x'), buf.Rectangle)
+	empty.SetCell(NewCell('x'), image.Pt(5, 0))
+	if cell := buf.GetCell(image.Pt(5, 0)); cell != CellClear {
+		t.Errorf("expected an empty view to ignore writes, got %+v", cell)
+	}
+}
+
+func TestBufferLocal(t *testing.T) {
+	buf := NewBuffer(image.Rect(10, 10, 16, 14))
+	local := buf.Sub(image.Rect(11, 11, 14, 13)).Local()
+	if local.Rectangle != image.Rect(0, 0, 3, 2) {
+		t.Fatalf("expected %v, got %v", image.Rect(0, 0, 3, 2), local.Rectangle)
+	}
+	local.SetCell(NewCell('a'), image.Pt(0, 0))
+	local.SetCell(NewCell('x'), image.Pt(3, 0))
+	local.SetCell(NewCell('x'), image.Pt(-1, 0))
+	local.Fill(NewCell('b'), image.Rect(1, 1, 5, 5))
+
+	// a view of a local view, made local again
+	nested := local.Sub(image.Rect(2, 0, 3, 1)).Local()
+	nested.SetCell(NewCell('c'), image.Pt(0, 0))
+	expectText(t, buf, buf.Rectangle,
+		"      ",
+		" a c  ",
+		"  bb  ",
+		"      ",
+	)
+	if cell := nested.GetCell(image.Pt(0, 0)); cell.Rune != 'c' {
+		t.Errorf("expected %q, got %q", 'c', cell.Rune)
+	}
+	if cell := local.GetCell(image.Pt(1, 1)); cell.Rune != 'b' {
+		t.Errorf("expected %q, got %q", 'b', cell.Rune)
+	}
+}
+
+func TestBufferCellMap(t *testing.T) {
+	buf := NewBuffer(image.Rect(1, 1, 4, 3))
+	buf.SetCell(NewCell('a'), image.Pt(3, 2))
+	cellMap := buf.CellMap()
+	if len(cellMap) != 6 {
+		t.Errorf("expected 6 cells, got %d", len(cellMap))
+	}
+	for p, cell := range cellMap {
+		if !p.In(buf.Rectangle) {
+			t.Errorf("unexpected point %v", p)
+		}
+		if cell != buf.GetCell(p) {
+			t.Errorf("%v: expected %+v, got %+v", p, buf.GetCell(p), cell)
+		}
+	}
+	if cellMap[image.Pt(3, 2)].Rune != 'a' {
+		t.Errorf("expected %q, got %q", 'a', cellMap[image.Pt(3, 2)].Rune)
+	}
+
+	// views only return their own cells, in their own coordinates
+	local := buf.Sub(image.Rect(2, 2, 4, 3)).Local()
+	expected := map[image.Point]Cell{
+		image.Pt(0, 0): CellClear,
+		image.Pt(1, 0): NewCell('a'),
+	}
+	if cellMap := local.CellMap(); !reflect.DeepEqual(cellMap, expected) {
+		t.Errorf("expected %v, got %v", expected, cellMap)
+	}
+}
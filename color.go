@@ -0,0 +1,176 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// colorRGBFlag marks a Color holding a 24-bit RGB value instead of a palette index.
+const colorRGBFlag Color = 1 << 24
+
+// ColorMode is the set of colors a terminal is able to display.
+type ColorMode uint
+
+const (
+	// ColorMode16 is limited to the 8 basic colors and their bright variants.
+	ColorMode16 ColorMode = iota
+	// ColorMode256 supports the xterm 256 color palette.
+	ColorMode256
+	// ColorModeTrueColor supports 24-bit RGB colors.
+	ColorModeTrueColor
+)
+
+// ColorRGB returns a 24-bit Color.
+// It is downgraded to the nearest palette color on terminals which don't support truecolor.
+func ColorRGB(r, g, b uint8) Color {
+	return colorRGBFlag | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// IsRGB reports whether the Color is a 24-bit RGB color rather than a palette index.
+func (self Color) IsRGB() bool {
+	return self >= 0 && self&colorRGBFlag != 0
+}
+
+// RGB returns the red, green and blue components of the Color.
+// Palette colors are converted using the xterm default palette.
+// ColorClear is reported as black.
+func (self Color) RGB() (uint8, uint8, uint8) {
+	switch {
+	case self.IsRGB():
+		return uint8(self >> 16), uint8(self >> 8), uint8(self)
+	case self < 0 || self > 255:
+		return 0, 0, 0
+	case self < 16:
+		c := ansiColors[self]
+		return c[0], c[1], c[2]
+	case self < 232:
+		i := int(self) - 16
+		return cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]
+	default:
+		gray := uint8(8 + 10*(int(self)-232))
+		return gray, gray, gray
+	}
+}
+
+// Downgrade returns the nearest Color which can be displayed in the given ColorMode.
+func (self Color) Downgrade(mode ColorMode) Color {
+	switch {
+	case self == ColorClear, mode == ColorModeTrueColor:
+		return self
+	case mode == ColorMode256 && !self.IsRGB():
+		return self
+	case mode == ColorMode16 && self >= 0 && self < 16:
+		return self
+	}
+	r, g, b := self.RGB()
+	if mode == ColorMode256 {
+		return nearestColor256(r, g, b)
+	}
+	return nearestColor(r, g, b, 0, 16)
+}
+
+// ansiColors are the RGB values of the 16 basic colors in the xterm default palette.
+var ansiColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the xterm 256 color palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// nearestColor256 returns the color of the 6x6x6 cube or of the grayscale ramp nearest to r, g, b.
+func nearestColor256(r, g, b uint8) Color {
+	cubeIndex := func(v uint8) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (int(v) - 35) / 40
+	}
+	cube := Color(16 + 36*cubeIndex(r) + 6*cubeIndex(g) + cubeIndex(b))
+
+	average := (int(r) + int(g) + int(b)) / 3
+	if average < 8 {
+		return cube
+	}
+	gray := Color(232 + MinInt((average-3)/10, 23))
+
+	if colorDistance(gray, r, g, b) < colorDistance(cube, r, g, b) {
+		return gray
+	}
+	return cube
+}
+
+// nearestColor returns the palette color in [from, to) nearest to r, g, b.
+func nearestColor(r, g, b uint8, from, to Color) Color {
+	nearest := from
+	for c := from; c < to; c++ {
+		if colorDistance(c, r, g, b) < colorDistance(nearest, r, g, b) {
+			nearest = c
+		}
+	}
+	return nearest
+}
+
+func colorDistance(c Color, r, g, b uint8) int {
+	cr, cg, cb := c.RGB()
+	dr, dg, db := int(cr)-int(r), int(cg)-int(g), int(cb)-int(b)
+	return dr*dr + dg*dg + db*db
+}
+
+// parseHexColor parses colors of the form #rgb or #rrggbb.
+func parseHexColor(s string) (Color, error) {
+	if !strings.HasPrefix(s, "#") {
+		return ColorClear, fmt.Errorf("hex color %q must start with #", s)
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return ColorClear, fmt.Errorf("hex color %q must have 3 or 6 digits", s)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return ColorClear, fmt.Errorf("invalid hex color %q", s)
+	}
+	return ColorRGB(uint8(value>>16), uint8(value>>8), uint8(value)), nil
+}
+
+// DetectColorMode guesses the ColorMode of the terminal from the COLORTERM and TERM
+// environment variables and from the terminfo database.
+// Terminals which can't be identified are assumed to support 256 colors.
+func DetectColorMode() ColorMode {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorModeTrueColor
+	}
+
+	term := os.Getenv("TERM")
+	if strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") {
+		return ColorModeTrueColor
+	}
+
+	if colors, ok := terminfoColors(term); ok {
+		switch {
+		case colors >= 1<<24:
+			return ColorModeTrueColor
+		case colors >= 256:
+			return ColorMode256
+		default:
+			return ColorMode16
+		}
+	}
+
+	return ColorMode256
+}
@@ -0,0 +1,323 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// GlobalScope is the name of the keymap which is always active.
+const GlobalScope = "global"
+
+// DefaultChordTimeout is the default delay allowed between the keys of a chord.
+const DefaultChordTimeout = time.Second
+
+// Binding describes a key sequence bound to a handler, as listed by Dispatcher.Bindings.
+type Binding struct {
+	Scope string
+	// Keys is the sequence of event IDs separated by spaces, e.g. `<C-x> <C-s>`.
+	Keys        string
+	Description string
+}
+
+type binding struct {
+	keys        []string
+	description string
+	handler     func(Event)
+}
+
+// Keymap holds the bindings of a scope.
+type Keymap struct {
+	name       string
+	bindings   []*binding
+	dispatcher *Dispatcher
+}
+
+// Bind binds a key sequence to a handler, replacing any previous binding of the sequence.
+// keys is a list of event IDs separated by spaces, like `q`, `<C-c>`, `g g` or `<C-x> <C-s>`.
+// The handler receives the last event of the sequence.
+func (self *Keymap) Bind(keys string, description string, handler func(Event)) {
+	sequence := parseSequence(keys)
+	if len(sequence) == 0 {
+		return
+	}
+	self.dispatcher.Lock()
+	defer self.dispatcher.Unlock()
+	b := &binding{sequence, description, handler}
+	for i, other := range self.bindings {
+		if sameKeys(other.keys, sequence) {
+			self.bindings[i] = b
+			return
+		}
+	}
+	self.bindings = append(self.bindings, b)
+}
+
+// parseSequence splits keys into event IDs, writing the keys like Key.String does,
+// so that `<C-M-a>` matches the `<M-<C-a>>` events.
+func parseSequence(keys string) []string {
+	sequence := strings.Fields(keys)
+	for i, id := range sequence {
+		if key, ok := ParseKey(id); ok {
+			sequence[i] = key.String()
+		}
+	}
+	return sequence
+}
+
+// Unbind removes the binding of a key sequence.
+func (self *Keymap) Unbind(keys string) {
+	sequence := parseSequence(keys)
+	self.dispatcher.Lock()
+	defer self.dispatcher.Unlock()
+	for i, other := range self.bindings {
+		if sameKeys(other.keys, sequence) {
+			self.bindings = append(self.bindings[:i], self.bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// match returns the binding matching the sequence exactly, if any,
+// and whether a longer binding starts with the sequence.
+func (self *Keymap) match(sequence []string) (*binding, bool) {
+	var exact *binding
+	prefix := false
+	for _, b := range self.bindings {
+		switch {
+		case sameKeys(b.keys, sequence):
+			exact = b
+		case len(b.keys) > len(sequence) && sameKeys(b.keys[:len(sequence)], sequence):
+			prefix = true
+		}
+	}
+	return exact, prefix
+}
+
+func sameKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Dispatcher calls the handlers bound to event IDs and key chords.
+// Bindings of the active scopes take precedence over the ones of the global scope.
+//
+// When a sequence is both bound and the beginning of a longer chord, like `g` and `g g`,
+// the dispatcher waits for the next key: the handler of `g` is called once the next key
+// doesn't continue the chord, or by Expire once the chord timed out.
+type Dispatcher struct {
+	// ChordTimeout is the maximum delay between the keys of a chord, DefaultChordTimeout if zero.
+	ChordTimeout time.Duration
+
+	keymaps []*Keymap
+	active  []*Keymap
+
+	pending     []string
+	pendingTime time.Time
+	// pendingBinding is the binding of the pending sequence, if any
+	pendingBinding *binding
+	pendingEvent   Event
+
+	// now returns the current time, it is replaced in tests and is nil in a zero Dispatcher
+	now func() time.Time
+
+	sync.Mutex
+}
+
+func NewDispatcher() *Dispatcher {
+	self := &Dispatcher{
+		ChordTimeout: DefaultChordTimeout,
+		now:          time.Now,
+	}
+	self.Keymap(GlobalScope)
+	return self
+}
+
+// Keymap returns the keymap of a scope, creating it if needed.
+func (self *Dispatcher) Keymap(scope string) *Keymap {
+	self.Lock()
+	defer self.Unlock()
+	return self.keymap(scope)
+}
+
+func (self *Dispatcher) keymap(scope string) *Keymap {
+	for _, keymap := range self.keymaps {
+		if keymap.name == scope {
+			return keymap
+		}
+	}
+	keymap := &Keymap{name: scope, dispatcher: self}
+	self.keymaps = append(self.keymaps, keymap)
+	return keymap
+}
+
+// Bind binds a key sequence in the global scope, see Keymap.Bind.
+func (self *Dispatcher) Bind(keys string, description string, handler func(Event)) {
+	self.Keymap(GlobalScope).Bind(keys, description, handler)
+}
+
+// SetScopes sets the scopes active in addition to the global scope, by decreasing priority,
+// e.g. the scope of the focused widget. Any pending chord is cancelled.
+func (self *Dispatcher) SetScopes(scopes ...string) {
+	self.Lock()
+	defer self.Unlock()
+	self.active = self.active[:0]
+	for _, scope := range scopes {
+		if scope != GlobalScope {
+			self.active = append(self.active, self.keymap(scope))
+		}
+	}
+	self.resetPending()
+}
+
+// Scopes returns the active scopes, excluding the global scope.
+func (self *Dispatcher) Scopes() []string {
+	self.Lock()
+	defer self.Unlock()
+	scopes := make([]string, len(self.active))
+	for i, keymap := range self.active {
+		scopes[i] = keymap.name
+	}
+	return scopes
+}
+
+// Bindings returns all the bindings, grouped by scope in the order the scopes were created.
+func (self *Dispatcher) Bindings() []Binding {
+	self.Lock()
+	defer self.Unlock()
+	bindings := []Binding{}
+	for _, keymap := range self.keymaps {
+		for _, b := range keymap.bindings {
+			bindings = append(bindings, Binding{
+				Scope:       keymap.name,
+				Keys:        strings.Join(b.keys, " "),
+				Description: b.description,
+			})
+		}
+	}
+	return bindings
+}
+
+// Pending returns the keys of the chord typed so far, separated by spaces.
+func (self *Dispatcher) Pending() string {
+	self.Lock()
+	defer self.Unlock()
+	return strings.Join(self.pending, " ")
+}
+
+// timedOut reports whether the pending chord timed out.
+func (self *Dispatcher) timedOut(now time.Time) bool {
+	timeout := self.ChordTimeout
+	if timeout == 0 {
+		timeout = DefaultChordTimeout
+	}
+	return now.Sub(self.pendingTime) > timeout
+}
+
+func (self *Dispatcher) clock() time.Time {
+	if self.now == nil {
+		return time.Now()
+	}
+	return self.now()
+}
+
+func (self *Dispatcher) resetPending() {
+	self.pending = nil
+	self.pendingBinding = nil
+}
+
+// match looks for the sequence in the active keymaps, then in the global one.
+// The first keymap in which the sequence is bound or starts a chord wins.
+func (self *Dispatcher) match(sequence []string) (*binding, bool) {
+	keymaps := append(append([]*Keymap{}, self.active...), self.keymap(GlobalScope))
+	for _, keymap := range keymaps {
+		if exact, prefix := keymap.match(sequence); exact != nil || prefix {
+			return exact, prefix
+		}
+	}
+	return nil, false
+}
+
+// Dispatch calls the handler bound to the event, or to the chord it completes.
+// It reports whether the event was used, either by a handler or as part of a pending chord.
+// Handlers are called without the dispatcher locked, so they can change the bindings.
+func (self *Dispatcher) Dispatch(e Event) bool {
+	self.Lock()
+	now := self.clock()
+	var handlers []func(Event)
+	var events []Event
+
+	if len(self.pending) > 0 && self.timedOut(now) {
+		if self.pendingBinding != nil {
+			handlers = append(handlers, self.pendingBinding.handler)
+			events = append(events, self.pendingEvent)
+		}
+		self.resetPending()
+	}
+
+	used := false
+	for {
+		sequence := append(append([]string{}, self.pending...), e.ID)
+		exact, prefix := self.match(sequence)
+		if prefix {
+			self.pending = sequence
+			self.pendingTime = now
+			self.pendingBinding = exact
+			self.pendingEvent = e
+			used = true
+			break
+		}
+		if exact != nil {
+			handlers = append(handlers, exact.handler)
+			events = append(events, e)
+			self.resetPending()
+			used = true
+			break
+		}
+		if len(self.pending) == 0 {
+			break
+		}
+		// the event doesn't continue the chord, which is abandoned
+		if self.pendingBinding != nil {
+			handlers = append(handlers, self.pendingBinding.handler)
+			events = append(events, self.pendingEvent)
+		}
+		self.resetPending()
+	}
+	self.Unlock()
+
+	for i, handler := range handlers {
+		handler(events[i])
+	}
+	return used || len(handlers) > 0
+}
+
+// Expire cancels the pending chord if it timed out, calling the handler of the keys typed so far
+// if they are bound. It reports whether a handler was called.
+func (self *Dispatcher) Expire() bool {
+	self.Lock()
+	if len(self.pending) == 0 || !self.timedOut(self.clock()) {
+		self.Unlock()
+		return false
+	}
+	b, e := self.pendingBinding, self.pendingEvent
+	self.resetPending()
+	self.Unlock()
+
+	if b == nil {
+		return false
+	}
+	b.handler(e)
+	return true
+}
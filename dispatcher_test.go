@@ -0,0 +1,286 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testDispatcher is a Dispatcher with a fake clock recording the handlers it calls.
+type testDispatcher struct {
+	*Dispatcher
+	clock time.Time
+	calls []string
+}
+
+func newTestDispatcher() *testDispatcher {
+	self := &testDispatcher{
+		Dispatcher: NewDispatcher(),
+		clock:      time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	self.now = func() time.Time { return self.clock }
+	return self
+}
+
+// handler returns a handler recording its name and the ID of the event it received.
+func (self *testDispatcher) handler(name string) func(Event) {
+	return func(e Event) {
+		self.calls = append(self.calls, name+":"+e.ID)
+	}
+}
+
+func (self *testDispatcher) advance(d time.Duration) {
+	self.clock = self.clock.Add(d)
+}
+
+func (self *testDispatcher) dispatch(ids ...string) []bool {
+	used := []bool{}
+	for _, id := range ids {
+		used = append(used, self.Dispatch(Event{Type: KeyboardEvent, ID: id}))
+	}
+	return used
+}
+
+func (self *testDispatcher) expectCalls(t *testing.T, calls ...string) {
+	t.Helper()
+	if len(calls) == 0 && len(self.calls) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(self.calls, calls) {
+		t.Errorf("expected calls %q, got %q", calls, self.calls)
+	}
+	self.calls = nil
+}
+
+func TestDispatcherChords(t *testing.T) {
+	testCases := []struct {
+		name     string
+		bindings []string
+		ids      []string
+		used     []bool
+		calls    []string
+		pending  string
+	}{
+		{"single key", []string{"q"}, []string{"q"}, []bool{true}, []string{"q:q"}, ""},
+		{"unbound key", []string{"q"}, []string{"x"}, []bool{false}, nil, ""},
+		{"chord", []string{"g g"}, []string{"g", "g"}, []bool{true, true}, []string{"g g:g"}, ""},
+		{"chord pending", []string{"<C-x> <C-s>"}, []string{"<C-x>"}, []bool{true}, nil, "<C-x>"},
+		{"abandoned chord", []string{"g g"}, []string{"g", "x"}, []bool{true, false}, nil, ""},
+		{"abandoned chord restarts", []string{"g g", "x"}, []string{"g", "x"}, []bool{true, true}, []string{"x:x"}, ""},
+		{"abandoned prefix binding", []string{"g", "g g"}, []string{"g", "x"}, []bool{true, true}, []string{"g:g"}, ""},
+		{"abandoned prefix binding and key", []string{"g", "g g", "x"}, []string{"g", "x"}, []bool{true, true}, []string{"g:g", "x:x"}, ""},
+		{"prefix binding completed", []string{"g", "g g"}, []string{"g", "g"}, []bool{true, true}, []string{"g g:g"}, ""},
+		{"prefix binding restarts", []string{"g", "g g", "d d"}, []string{"g", "d"}, []bool{true, true}, []string{"g:g"}, "d"},
+		{"longer chord", []string{"a b c"}, []string{"a", "b", "c"}, []bool{true, true, true}, []string{"a b c:c"}, ""},
+	}
+	for _, tc := range testCases {
+		d := newTestDispatcher()
+		for _, keys := range tc.bindings {
+			d.Bind(keys, "", d.handler(keys))
+		}
+		if used := d.dispatch(tc.ids...); !reflect.DeepEqual(used, tc.used) {
+			t.Errorf("%s: expected used %v, got %v", tc.name, tc.used, used)
+		}
+		if !reflect.DeepEqual(d.calls, tc.calls) {
+			t.Errorf("%s: expected calls %q, got %q", tc.name, tc.calls, d.calls)
+		}
+		if pending := d.Pending(); pending != tc.pending {
+			t.Errorf("%s: expected pending %q, got %q", tc.name, tc.pending, pending)
+		}
+	}
+}
+
+func TestDispatcherChordTimeout(t *testing.T) {
+	d := newTestDispatcher()
+	d.Bind("g g", "", d.handler("g g"))
+
+	d.dispatch("g")
+	d.advance(DefaultChordTimeout)
+	d.dispatch("g")
+	d.expectCalls(t, "g g:g")
+
+	// the second key comes too late and starts a new chord
+	d.dispatch("g")
+	d.advance(DefaultChordTimeout + time.Millisecond)
+	d.dispatch("g")
+	d.expectCalls(t)
+	if pending := d.Pending(); pending != "g" {
+		t.Errorf("expected pending %q, got %q", "g", pending)
+	}
+}
+
+func TestDispatcherChordTimeoutCallsPrefixBinding(t *testing.T) {
+	d := newTestDispatcher()
+	d.ChordTimeout = 100 * time.Millisecond
+	d.Bind("g", "", d.handler("g"))
+	d.Bind("g g", "", d.handler("g g"))
+	d.Bind("x", "", d.handler("x"))
+
+	d.dispatch("g")
+	d.advance(200 * time.Millisecond)
+	d.dispatch("x")
+	d.expectCalls(t, "g:g", "x:x")
+}
+
+func TestDispatcherExpire(t *testing.T) {
+	d := newTestDispatcher()
+	d.Bind("g", "", d.handler("g"))
+	d.Bind("g g", "", d.handler("g g"))
+	d.Bind("<C-x> <C-s>", "", d.handler("<C-x> <C-s>"))
+
+	if d.Expire() {
+		t.Error("expected nothing to expire without a pending chord")
+	}
+
+	d.dispatch("g")
+	d.advance(DefaultChordTimeout)
+	if d.Expire() {
+		t.Error("expected the chord not to expire before its timeout")
+	}
+	d.expectCalls(t)
+
+	d.advance(time.Millisecond)
+	if !d.Expire() {
+		t.Error("expected the chord to expire after its timeout")
+	}
+	d.expectCalls(t, "g:g")
+	if pending := d.Pending(); pending != "" {
+		t.Errorf("expected no pending chord, got %q", pending)
+	}
+	if d.Expire() {
+		t.Error("expected a chord to expire only once")
+	}
+
+	// an unbound prefix expires without calling a handler
+	d.dispatch("<C-x>")
+	d.advance(2 * DefaultChordTimeout)
+	if d.Expire() {
+		t.Error("expected no handler for an unbound prefix")
+	}
+	d.expectCalls(t)
+	if pending := d.Pending(); pending != "" {
+		t.Errorf("expected no pending chord, got %q", pending)
+	}
+}
+
+func TestDispatcherScopes(t *testing.T) {
+	d := newTestDispatcher()
+	d.Bind("q", "", d.handler("global q"))
+	d.Bind("g", "", d.handler("global g"))
+	d.Bind("x", "", d.handler("global x"))
+	d.Keymap("list").Bind("q", "", d.handler("list q"))
+	d.Keymap("list").Bind("g g", "", d.handler("list g g"))
+	d.Keymap("dialog").Bind("q", "", d.handler("dialog q"))
+
+	d.dispatch("q")
+	d.expectCalls(t, "global q:q")
+
+	d.SetScopes("list")
+	d.dispatch("q", "x")
+	d.expectCalls(t, "list q:q", "global x:x")
+
+	// the chord of the active scope shadows the global binding of its prefix
+	d.dispatch("g", "g")
+	d.expectCalls(t, "list g g:g")
+
+	d.SetScopes("dialog", "list")
+	d.dispatch("q")
+	d.expectCalls(t, "dialog q:q")
+	if scopes := d.Scopes(); !reflect.DeepEqual(scopes, []string{"dialog", "list"}) {
+		t.Errorf("expected scopes [dialog list], got %q", scopes)
+	}
+
+	d.SetScopes(GlobalScope)
+	d.dispatch("q", "g")
+	d.expectCalls(t, "global q:q", "global g:g")
+	if scopes := d.Scopes(); len(scopes) != 0 {
+		t.Errorf("expected no active scopes, got %q", scopes)
+	}
+}
+
+func TestDispatcherSetScopesCancelsChord(t *testing.T) {
+	d := newTestDispatcher()
+	d.Keymap("list").Bind("g g", "", d.handler("g g"))
+	d.SetScopes("list")
+	d.dispatch("g")
+	d.SetScopes("list")
+	if pending := d.Pending(); pending != "" {
+		t.Errorf("expected no pending chord, got %q", pending)
+	}
+	d.dispatch("g")
+	d.expectCalls(t)
+}
+
+func TestDispatcherBindings(t *testing.T) {
+	d := newTestDispatcher()
+	d.Bind("q", "quit", d.handler("q"))
+	d.Bind("g  g", "top", d.handler("g g"))
+	d.Keymap("list").Bind("j", "down", d.handler("j"))
+	d.Bind("q", "exit", d.handler("exit"))
+	d.Bind(" ", "ignored", d.handler("ignored"))
+
+	expected := []Binding{
+		{GlobalScope, "q", "exit"},
+		{GlobalScope, "g g", "top"},
+		{"list", "j", "down"},
+	}
+	if bindings := d.Bindings(); !reflect.DeepEqual(bindings, expected) {
+		t.Errorf("expected %+v, got %+v", expected, bindings)
+	}
+	d.dispatch("q")
+	d.expectCalls(t, "exit:q")
+
+	d.Keymap(GlobalScope).Unbind("g g")
+	d.Keymap(GlobalScope).Unbind("unknown")
+	if bindings := d.Bindings(); !reflect.DeepEqual(bindings, []Binding{expected[0], expected[2]}) {
+		t.Errorf("expected g g to be unbound, got %+v", bindings)
+	}
+}
+
+func TestDispatcherHandlerRebinds(t *testing.T) {
+	d := newTestDispatcher()
+	d.Bind("a", "", func(Event) {
+		d.Bind("b", "", d.handler("b"))
+		d.SetScopes("other")
+	})
+	d.dispatch("a", "b")
+	d.expectCalls(t, "b:b")
+}
+
+func TestDispatcherAltCtrlForms(t *testing.T) {
+	d := newTestDispatcher()
+	d.Bind("<C-M-a>", "", d.handler("flat"))
+	d.Bind("<M-<C-b>>", "", d.handler("nested"))
+	d.Bind("<C-M-S-<Up>>", "", d.handler("up"))
+
+	d.dispatch("<M-<C-a>>", "<M-<C-b>>", "<M-<C-S-<Up>>>")
+	d.expectCalls(t, "flat:<M-<C-a>>", "nested:<M-<C-b>>", "up:<M-<C-S-<Up>>>")
+
+	d.Keymap(GlobalScope).Unbind("<M-<C-a>>")
+	if bindings := d.Bindings(); len(bindings) != 2 || bindings[0].Keys != "<M-<C-b>>" {
+		t.Errorf("expected <C-M-a> to be unbound, got %+v", bindings)
+	}
+}
+
+func TestDispatcherZeroValue(t *testing.T) {
+	var d Dispatcher
+	calls := []string{}
+	d.Bind("g g", "", func(e Event) {
+		calls = append(calls, e.ID)
+	})
+	for _, id := range []string{"g", "g"} {
+		if !d.Dispatch(Event{Type: KeyboardEvent, ID: id}) {
+			t.Errorf("expected %s to be used", id)
+		}
+	}
+	if d.Expire() {
+		t.Error("expected no pending chord")
+	}
+	if !reflect.DeepEqual(calls, []string{"g"}) {
+		t.Errorf("expected the chord to be called once, got %v", calls)
+	}
+}
@@ -5,9 +5,8 @@
 package termui
 
 import (
-	"fmt"
-
-	tb "github.com/nsf/termbox-go"
+	"context"
+	"sync"
 )
 
 /*
@@ -15,28 +14,38 @@ List of events:
 	mouse events:
 		<MouseLeft> <MouseRight> <MouseMiddle>
 		<MouseWheelUp> <MouseWheelDown>
+		<MouseRelease> <MouseMove>
 	keyboard events:
 		any uppercase or lowercase letter like j or J
 		<C-d> etc
 		<M-d> etc
 		<Up> <Down> <Left> <Right>
-		<Insert> <Delete> <Home> <End> <Previous> <Next>
+		<Insert> <Delete> <Home> <End> <PageUp> <PageDown>
 		<Backspace> <Tab> <Enter> <Escape> <Space>
 		<C-<Space>> etc
+		<C-<Up>> <M-<Up>> <S-<Up>> <C-S-<Up>> etc
+		<S-<Tab>>
 	terminal events:
         <Resize>
+        <Paste>
+        <Error>
+
+    Keyboard events have a Key payload holding the key code, rune and modifiers.
+    Modifiers are combined in the order C-, S-, and M- wraps the ID of the key, e.g. <M-<C-a>>.
 
-    keyboard events that do not work:
-        <C-->
-        <C-2> <C-~>
-        <C-h>
-        <C-i>
-        <C-m>
-        <C-[> <C-3>
-        <C-\\>
-        <C-]>
-        <C-/> <C-_>
-        <C-8>
+    keyboard events which only work on terminals supporting modifyOtherKeys or the CSI u protocol,
+    as they are otherwise sent like other keys:
+        <C-h> (<C-<Backspace>>)
+        <C-i> (<Tab>)
+        <C-m> (<Enter>)
+        <C-[> (<Escape>)
+        <C-\\> (<C-4>)
+        <C-]> (<C-5>)
+        <C-/> <C-_> (<C-7>)
+        <C-2> <C-~> (<C-<Space>>)
+        <C-3> (<Escape>)
+        <C-8> (<Backspace>)
+        <C--> (-)
 */
 
 type EventType uint
@@ -45,6 +54,14 @@ const (
 	KeyboardEvent EventType = iota
 	MouseEvent
 	ResizeEvent
+	// PasteEvent holds text pasted in a terminal supporting bracketed paste.
+	PasteEvent
+	// ErrorEvent reports an error of the backend, which is the payload of the event.
+	// No event is polled after an error.
+	ErrorEvent
+	// InterruptEvent is returned by Backend.PollEvent after a call to Backend.Interrupt.
+	// It is never sent to the channels of PollEvents.
+	InterruptEvent
 )
 
 type Event struct {
@@ -54,10 +71,25 @@ type Event struct {
 }
 
 // Mouse payload.
+// Modifiers are only reported by terminals using the SGR or X10 mouse encodings.
 type Mouse struct {
+	// Drag is true if Action is MouseActionDrag.
 	Drag bool
 	X    int
 	Y    int
+
+	Button MouseButton
+	Action MouseAction
+	Mod    KeyModifier
+	// Clicks is 2 for a double click and 3 for a triple click, and is reported
+	// by both the press and the release of the button.
+	Clicks int
+}
+
+// Paste payload.
+// Line breaks of the pasted text are converted to \n.
+type Paste struct {
+	Text string
 }
 
 // Resize payload.
@@ -66,146 +98,252 @@ type Resize struct {
 	Height int
 }
 
-// PollEvents gets events from termbox, converts them, then sends them to each of its channels.
+// subscriber is a channel returned by PollEventsContext.
+// Its events are queued and sent to the channel by its own goroutine, so that a channel
+// which isn't drained doesn't block the other ones.
+type subscriber struct {
+	ch    chan Event
+	queue []Event
+	// ended is set once no more events are queued, the channel being closed after the queued ones
+	ended bool
+	// notify wakes up the goroutine of the subscriber
+	notify chan struct{}
+	// closed is closed when the subscriber is cancelled or its goroutine returned
+	closed    chan struct{}
+	closeOnce sync.Once
+	sync.Mutex
+}
+
+func newSubscriber() *subscriber {
+	self := &subscriber{
+		ch:     make(chan Event),
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+	go self.forward()
+	return self
+}
+
+// send queues an event. It never blocks.
+func (self *subscriber) send(e Event) {
+	self.Lock()
+	if !self.ended {
+		self.queue = append(self.queue, e)
+	}
+	self.Unlock()
+	self.wake()
+}
+
+// end closes the channel once the queued events have been received.
+func (self *subscriber) end() {
+	self.Lock()
+	self.ended = true
+	self.Unlock()
+	self.wake()
+}
+
+// close closes the channel, dropping the queued events.
+func (self *subscriber) close() {
+	self.closeOnce.Do(func() {
+		close(self.closed)
+	})
+}
+
+func (self *subscriber) wake() {
+	select {
+	case self.notify <- struct{}{}:
+	default:
+	}
+}
+
+// forward sends the queued events to the channel until the subscriber is closed or ended.
+func (self *subscriber) forward() {
+	defer close(self.ch)
+	defer self.close()
+	for {
+		self.Lock()
+		events, ended := self.queue, self.ended
+		self.queue = nil
+		self.Unlock()
+		for _, e := range events {
+			select {
+			case self.ch <- e:
+			case <-self.closed:
+				return
+			}
+		}
+		if ended {
+			return
+		}
+		select {
+		case <-self.notify:
+		case <-self.closed:
+			return
+		}
+	}
+}
+
+// poller reads events from the backend in a single goroutine and sends them to every subscriber.
+// The goroutine runs as long as there are subscribers.
+var poller = struct {
+	subscribers map[*subscriber]bool
+	stop        chan struct{}
+	done        chan struct{}
+	// pending holds the events polled while the goroutine was stopping, for the next one
+	pending []Event
+	// control serializes starting and stopping the goroutine
+	control sync.Mutex
+	sync.Mutex
+}{
+	subscribers: make(map[*subscriber]bool),
+}
+
+// PollEvents gets events from the backend, then sends them to its channel.
+// The channel is closed by `Close`.
 func PollEvents() <-chan Event {
-	ch := make(chan Event)
+	return PollEventsContext(context.Background())
+}
+
+// PollEventsContext gets events from the backend, then sends them to its channel
+// until ctx is done, at which point the channel is closed.
+// Every channel returned by PollEvents and PollEventsContext receives every event.
+// Backend errors are sent as an ErrorEvent, after which all the channels are closed.
+//
+// The events are queued for each channel, so that a channel which isn't drained doesn't
+// block the other ones. Cancel the context of a channel which isn't read anymore.
+func PollEventsContext(ctx context.Context) <-chan Event {
+	sub := newSubscriber()
+
+	poller.control.Lock()
+	poller.Lock()
+	poller.subscribers[sub] = true
+	if poller.done == nil {
+		poller.stop = make(chan struct{})
+		poller.done = make(chan struct{})
+		go pollEvents(poller.stop, poller.done)
+	}
+	poller.Unlock()
+	poller.control.Unlock()
+
 	go func() {
-		for {
-			ch <- convertTermboxEvent(tb.PollEvent())
+		select {
+		case <-ctx.Done():
+			unsubscribe(sub)
+		case <-sub.closed:
 		}
 	}()
-	return ch
-}
-
-var keyboardMap = map[tb.Key]string{
-	tb.KeyF1:         "<F1>",
-	tb.KeyF2:         "<F2>",
-	tb.KeyF3:         "<F3>",
-	tb.KeyF4:         "<F4>",
-	tb.KeyF5:         "<F5>",
-	tb.KeyF6:         "<F6>",
-	tb.KeyF7:         "<F7>",
-	tb.KeyF8:         "<F8>",
-	tb.KeyF9:         "<F9>",
-	tb.KeyF10:        "<F10>",
-	tb.KeyF11:        "<F11>",
-	tb.KeyF12:        "<F12>",
-	tb.KeyInsert:     "<Insert>",
-	tb.KeyDelete:     "<Delete>",
-	tb.KeyHome:       "<Home>",
-	tb.KeyEnd:        "<End>",
-	tb.KeyPgup:       "<PageUp>",
-	tb.KeyPgdn:       "<PageDown>",
-	tb.KeyArrowUp:    "<Up>",
-	tb.KeyArrowDown:  "<Down>",
-	tb.KeyArrowLeft:  "<Left>",
-	tb.KeyArrowRight: "<Right>",
-
-	tb.KeyCtrlSpace:  "<C-<Space>>", // tb.KeyCtrl2 tb.KeyCtrlTilde
-	tb.KeyCtrlA:      "<C-a>",
-	tb.KeyCtrlB:      "<C-b>",
-	tb.KeyCtrlC:      "<C-c>",
-	tb.KeyCtrlD:      "<C-d>",
-	tb.KeyCtrlE:      "<C-e>",
-	tb.KeyCtrlF:      "<C-f>",
-	tb.KeyCtrlG:      "<C-g>",
-	tb.KeyBackspace:  "<C-<Backspace>>", // tb.KeyCtrlH
-	tb.KeyTab:        "<Tab>",           // tb.KeyCtrlI
-	tb.KeyCtrlJ:      "<C-j>",
-	tb.KeyCtrlK:      "<C-k>",
-	tb.KeyCtrlL:      "<C-l>",
-	tb.KeyEnter:      "<Enter>", // tb.KeyCtrlM
-	tb.KeyCtrlN:      "<C-n>",
-	tb.KeyCtrlO:      "<C-o>",
-	tb.KeyCtrlP:      "<C-p>",
-	tb.KeyCtrlQ:      "<C-q>",
-	tb.KeyCtrlR:      "<C-r>",
-	tb.KeyCtrlS:      "<C-s>",
-	tb.KeyCtrlT:      "<C-t>",
-	tb.KeyCtrlU:      "<C-u>",
-	tb.KeyCtrlV:      "<C-v>",
-	tb.KeyCtrlW:      "<C-w>",
-	tb.KeyCtrlX:      "<C-x>",
-	tb.KeyCtrlY:      "<C-y>",
-	tb.KeyCtrlZ:      "<C-z>",
-	tb.KeyEsc:        "<Escape>", // tb.KeyCtrlLsqBracket tb.KeyCtrl3
-	tb.KeyCtrl4:      "<C-4>",    // tb.KeyCtrlBackslash
-	tb.KeyCtrl5:      "<C-5>",    // tb.KeyCtrlRsqBracket
-	tb.KeyCtrl6:      "<C-6>",
-	tb.KeyCtrl7:      "<C-7>", // tb.KeyCtrlSlash tb.KeyCtrlUnderscore
-	tb.KeySpace:      "<Space>",
-	tb.KeyBackspace2: "<Backspace>", // tb.KeyCtrl8:
-}
-
-// convertTermboxKeyboardEvent converts a termbox keyboard event to a more friendly string format.
-// Combines modifiers into the string instead of having them as additional fields in an event.
-func convertTermboxKeyboardEvent(e tb.Event) Event {
-	ID := "%s"
-	if e.Mod == tb.ModAlt {
-		ID = "<M-%s>"
-	}
+	return sub.ch
+}
 
-	if e.Ch != 0 {
-		ID = fmt.Sprintf(ID, string(e.Ch))
-	} else {
-		converted, ok := keyboardMap[e.Key]
-		if !ok {
-			converted = ""
+// pollEvents sends the events of the backend to the subscribers until stop is closed.
+func pollEvents(stop, done chan struct{}) {
+	defer close(done)
+	poller.Lock()
+	pending := poller.pending
+	poller.pending = nil
+	poller.Unlock()
+	for _, e := range pending {
+		if !broadcast(e, done) {
+			return
 		}
-		ID = fmt.Sprintf(ID, converted)
 	}
 
-	return Event{
-		Type: KeyboardEvent,
-		ID:   ID,
+	for {
+		e := backend.PollEvent()
+		select {
+		case <-stop:
+			// the event was polled before the interrupt, keep it for the next subscribers
+			if e.Type != InterruptEvent {
+				poller.Lock()
+				poller.pending = append(poller.pending, e)
+				poller.Unlock()
+			}
+			return
+		default:
+		}
+		if e.Type == InterruptEvent {
+			continue
+		}
+		if !broadcast(e, done) {
+			return
+		}
 	}
 }
 
-var mouseButtonMap = map[tb.Key]string{
-	tb.MouseLeft:      "<MouseLeft>",
-	tb.MouseMiddle:    "<MouseMiddle>",
-	tb.MouseRight:     "<MouseRight>",
-	tb.MouseRelease:   "<MouseRelease>",
-	tb.MouseWheelUp:   "<MouseWheelUp>",
-	tb.MouseWheelDown: "<MouseWheelDown>",
+// broadcast sends an event to every subscriber, and reports whether polling goes on.
+// After an ErrorEvent, the channels are closed once the subscribers received their events.
+func broadcast(e Event, done chan struct{}) bool {
+	poller.Lock()
+	subscribers := make([]*subscriber, 0, len(poller.subscribers))
+	for sub := range poller.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	poller.Unlock()
+	for _, sub := range subscribers {
+		sub.send(e)
+	}
+
+	if e.Type != ErrorEvent {
+		return true
+	}
+	poller.Lock()
+	if poller.done == done {
+		for sub := range poller.subscribers {
+			sub.end()
+		}
+		poller.subscribers = make(map[*subscriber]bool)
+		poller.stop, poller.done = nil, nil
+	}
+	poller.Unlock()
+	return false
 }
 
-func convertTermboxMouseEvent(e tb.Event) Event {
-	converted, ok := mouseButtonMap[e.Key]
-	if !ok {
-		converted = "Unknown_Mouse_Button"
+// unsubscribe removes a subscriber, stopping the poller if it was the last one.
+func unsubscribe(sub *subscriber) {
+	poller.control.Lock()
+	defer poller.control.Unlock()
+
+	poller.Lock()
+	delete(poller.subscribers, sub)
+	var stop, done chan struct{}
+	if len(poller.subscribers) == 0 {
+		stop, done = poller.stop, poller.done
+		poller.stop, poller.done = nil, nil
 	}
-	Drag := e.Mod == tb.ModMotion
-	return Event{
-		Type: MouseEvent,
-		ID:   converted,
-		Payload: Mouse{
-			X:    e.MouseX,
-			Y:    e.MouseY,
-			Drag: Drag,
-		},
+	poller.Unlock()
+
+	sub.close()
+	if done != nil {
+		stopPolling(stop, done)
 	}
 }
 
-// convertTermboxEvent turns a termbox event into a termui event.
-func convertTermboxEvent(e tb.Event) Event {
-	if e.Type == tb.EventError {
-		panic(e.Err)
+// stopPollingEvents stops the poller and closes the channels of all the subscribers.
+func stopPollingEvents() {
+	poller.control.Lock()
+	defer poller.control.Unlock()
+
+	poller.Lock()
+	subscribers := poller.subscribers
+	poller.subscribers = make(map[*subscriber]bool)
+	stop, done := poller.stop, poller.done
+	poller.stop, poller.done = nil, nil
+	poller.Unlock()
+
+	if done != nil {
+		stopPolling(stop, done)
 	}
-	switch e.Type {
-	case tb.EventKey:
-		return convertTermboxKeyboardEvent(e)
-	case tb.EventMouse:
-		return convertTermboxMouseEvent(e)
-	case tb.EventResize:
-		return Event{
-			Type: ResizeEvent,
-			ID:   "<Resize>",
-			Payload: Resize{
-				Width:  e.Width,
-				Height: e.Height,
-			},
-		}
+	// the events polled while stopping belong to the closed backend
+	poller.Lock()
+	poller.pending = nil
+	poller.Unlock()
+	for sub := range subscribers {
+		sub.close()
 	}
-	return Event{}
+}
+
+func stopPolling(stop, done chan struct{}) {
+	close(stop)
+	backend.Interrupt()
+	<-done
 }
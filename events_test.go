@@ -0,0 +1,124 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// receive returns the next event of the channel, failing the test after a second.
+func receive(t *testing.T, events <-chan Event) (Event, bool) {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		return e, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+	return Event{}, false
+}
+
+func TestPollEventsAbandonedChannel(t *testing.T) {
+	sim := NewSimulationBackend(10, 5)
+	SetBackend(sim)
+	defer SetBackend(NewTermboxBackend())
+	defer Close()
+
+	// never read, so that its buffer fills up
+	PollEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := PollEventsContext(ctx)
+
+	// more events than the abandoned channel can buffer
+	for i := 0; i < 2000; i++ {
+		sim.InjectKeys("j")
+		if e, _ := receive(t, events); e.ID != "j" {
+			t.Fatalf("event %d: expected j, got %q", i, e.ID)
+		}
+	}
+
+	cancel()
+	if _, ok := receive(t, events); ok {
+		t.Error("expected the channel to be closed once its context is done")
+	}
+}
+
+func TestPollEventsSlowChannel(t *testing.T) {
+	sim := NewSimulationBackend(10, 5)
+	SetBackend(sim)
+	defer SetBackend(NewTermboxBackend())
+	defer Close()
+
+	slow := PollEvents()
+	events := PollEvents()
+	for i := 0; i < 2000; i++ {
+		sim.InjectKeys("j")
+		receive(t, events)
+	}
+	sim.InjectKeys("k")
+	receive(t, events)
+
+	// the events of a channel which wasn't read are queued rather than dropped
+	for i := 0; i < 2000; i++ {
+		if e, _ := receive(t, slow); e.ID != "j" {
+			t.Fatalf("event %d: expected j, got %q", i, e.ID)
+		}
+	}
+	if e, _ := receive(t, slow); e.ID != "k" {
+		t.Errorf("expected k, got %q", e.ID)
+	}
+}
+
+func TestPollEventsErrorClosesAfterQueuedEvents(t *testing.T) {
+	sim := NewSimulationBackend(10, 5)
+	SetBackend(sim)
+	defer SetBackend(NewTermboxBackend())
+	defer Close()
+
+	events := PollEvents()
+	sim.InjectKeys("j")
+	sim.InjectEvent(Event{Type: ErrorEvent, ID: "<Error>"})
+	time.Sleep(10 * time.Millisecond)
+
+	for _, expected := range []string{"j", "<Error>"} {
+		if e, ok := receive(t, events); !ok || e.ID != expected {
+			t.Fatalf("expected %s, got %q", expected, e.ID)
+		}
+	}
+	if _, ok := receive(t, events); ok {
+		t.Error("expected the channel to be closed after the error")
+	}
+}
+
+// lateBackend returns a key polled after the poller was interrupted.
+type lateBackend struct {
+	*SimulationBackend
+}
+
+func (self lateBackend) Interrupt() {
+	self.InjectKeys("k")
+	self.SimulationBackend.Interrupt()
+}
+
+func TestPollEventsKeepsEventPolledWhileStopping(t *testing.T) {
+	sim := lateBackend{NewSimulationBackend(10, 5)}
+	SetBackend(sim)
+	defer SetBackend(NewTermboxBackend())
+	defer Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	PollEventsContext(ctx)
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+
+	events := PollEvents()
+	if e, _ := receive(t, events); e.ID != "k" {
+		t.Errorf("expected the key polled while stopping, got %q", e.ID)
+	}
+}
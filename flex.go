@@ -0,0 +1,310 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"math"
+)
+
+// FlexDirection is the main axis of a Flex.
+type FlexDirection uint
+
+const (
+	// FlexRow lays items out from left to right.
+	FlexRow FlexDirection = iota
+	// FlexColumn lays items out from top to bottom.
+	FlexColumn
+)
+
+// FlexJustify positions the items of a line along the main axis when they don't fill it.
+type FlexJustify uint
+
+const (
+	FlexJustifyStart FlexJustify = iota
+	FlexJustifyEnd
+	FlexJustifyCenter
+	FlexJustifySpaceBetween
+	FlexJustifySpaceAround
+)
+
+// FlexAlign positions items along the cross axis of their line.
+type FlexAlign uint
+
+const (
+	// FlexAlignAuto uses the Align of the Flex. It is the same as FlexAlignStretch for a Flex.
+	FlexAlignAuto FlexAlign = iota
+	FlexAlignStretch
+	FlexAlignStart
+	FlexAlignEnd
+	FlexAlignCenter
+)
+
+// FlexItem is a Drawable laid out by a Flex.
+type FlexItem struct {
+	Drawable
+	// Basis is the size of the item along the main axis before growing or shrinking.
+	Basis int
+	// CrossSize is the size of the item along the cross axis when it isn't stretched.
+	CrossSize int
+	// Grow shares the space left in the line between the items according to their weights.
+	Grow float64
+	// Shrink shares the missing space between the items according to their weights
+	// times their Basis, when the line overflows.
+	Shrink float64
+	// Align overrides the Align of the Flex for this item.
+	Align FlexAlign
+	// Auto replaces Basis and CrossSize by the preferred size of the item at each layout.
+	// The item must implement Sizer.
+	Auto bool
+}
+
+// Flex lays its items out along a row or a column, like a CSS flexbox.
+// Items keep their Basis size unless they grow or shrink, and can wrap onto several lines.
+// A Flex is a Drawable, so it can be nested in a Grid or in another Flex.
+type Flex struct {
+	Block
+	Direction FlexDirection
+	Justify   FlexJustify
+	Align     FlexAlign
+	// Wrap moves the items which don't fit in a line to a new line.
+	Wrap bool
+	// Gap is the number of cells between adjacent items and lines.
+	Gap   int
+	Items []*FlexItem
+}
+
+func NewFlex(direction FlexDirection) *Flex {
+	f := &Flex{
+		Block:     *NewBlock(),
+		Direction: direction,
+	}
+	f.Border = false
+	return f
+}
+
+// Add appends an item to the Flex and returns it so that its other fields can be set.
+// The item shrinks when the line overflows.
+// If basis is 0, the item is sized to fit its content if it implements Sizer,
+// and the size of its rect is used as its Basis and CrossSize otherwise.
+func (self *Flex) Add(item Drawable, basis int, grow float64) *FlexItem {
+	flexItem := &FlexItem{
+		Drawable: item,
+		Basis:    basis,
+		Grow:     grow,
+		Shrink:   1,
+	}
+	_, sizer := item.(Sizer)
+	flexItem.Auto = basis == 0 && sizer
+	main, cross := self.axes(item.GetRect().Size())
+	if basis == 0 {
+		flexItem.Basis = main
+	}
+	flexItem.CrossSize = cross
+	self.Items = append(self.Items, flexItem)
+	return flexItem
+}
+
+// area returns the rectangle in which the items are laid out.
+func (self *Flex) area() image.Rectangle {
+	return self.Inner
+}
+
+// axes returns the main and cross lengths of a size.
+func (self *Flex) axes(size image.Point) (int, int) {
+	if self.Direction == FlexColumn {
+		return size.Y, size.X
+	}
+	return size.X, size.Y
+}
+
+// rect converts main and cross axis coordinates to a rectangle.
+func (self *Flex) rect(main, cross, mainSize, crossSize int) image.Rectangle {
+	if self.Direction == FlexColumn {
+		return image.Rect(cross, main, cross+crossSize, main+mainSize)
+	}
+	return image.Rect(main, cross, main+mainSize, cross+crossSize)
+}
+
+// resize sets the Basis and CrossSize of the Auto items to their preferred size,
+// given the size of the area of the Flex.
+func (self *Flex) resize(area image.Point) {
+	for _, item := range self.Items {
+		sizer, ok := item.Drawable.(Sizer)
+		if !item.Auto || !ok {
+			continue
+		}
+		item.Basis, item.CrossSize = self.axes(sizer.PreferredSize(area.X))
+	}
+}
+
+// lines splits the items into lines fitting in length cells.
+func (self *Flex) lines(length int) [][]*FlexItem {
+	if !self.Wrap {
+		return [][]*FlexItem{self.Items}
+	}
+	lines := [][]*FlexItem{}
+	var line []*FlexItem
+	used := 0
+	for _, item := range self.Items {
+		if len(line) > 0 && used+self.Gap+item.Basis > length {
+			lines = append(lines, line)
+			line, used = nil, 0
+		}
+		if len(line) > 0 {
+			used += self.Gap
+		}
+		line = append(line, item)
+		used += item.Basis
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// mainSizes computes the sizes of the items of a line along the main axis.
+// It returns the sizes and the space left in the line.
+func (self *Flex) mainSizes(line []*FlexItem, length int) ([]int, int) {
+	free := length - self.Gap*(len(line)-1)
+	grow, shrink := 0.0, 0.0
+	for _, item := range line {
+		free -= item.Basis
+		grow += item.Grow
+		shrink += item.Shrink * float64(item.Basis)
+	}
+
+	sizes := make([]float64, len(line))
+	for i, item := range line {
+		sizes[i] = float64(item.Basis)
+		switch {
+		case free > 0 && grow > 0:
+			sizes[i] += float64(free) * item.Grow / grow
+		case free < 0 && shrink > 0:
+			sizes[i] += float64(free) * item.Shrink * float64(item.Basis) / shrink
+		}
+	}
+	if (free > 0 && grow > 0) || (free < 0 && shrink > 0) {
+		free = 0
+	}
+
+	// round cumulative sizes so that grown items fill the line without gaps
+	result := make([]int, len(line))
+	total := 0.0
+	previous := 0
+	for i, size := range sizes {
+		total += math.Max(size, 0)
+		end := int(math.Floor(total + 1e-6))
+		result[i] = end - previous
+		previous = end
+	}
+	return result, MaxInt(free, 0)
+}
+
+// layout sets the rects of the items.
+func (self *Flex) layout() {
+	area := self.area()
+	self.resize(area.Size())
+	mainStart, crossStart := self.axes(area.Min)
+	mainLength, crossLength := self.axes(area.Size())
+
+	lines := self.lines(mainLength)
+	crossSizes := make([]int, len(lines))
+	for i, line := range lines {
+		if len(lines) == 1 {
+			crossSizes[i] = crossLength
+			continue
+		}
+		for _, item := range line {
+			crossSizes[i] = MaxInt(crossSizes[i], item.CrossSize)
+		}
+	}
+
+	cross := crossStart
+	for l, line := range lines {
+		sizes, free := self.mainSizes(line, mainLength)
+
+		main, spacing := mainStart, 0
+		switch self.Justify {
+		case FlexJustifyEnd:
+			main += free
+		case FlexJustifyCenter:
+			main += free / 2
+		case FlexJustifySpaceBetween:
+			if len(line) > 1 {
+				spacing = free / (len(line) - 1)
+			}
+		case FlexJustifySpaceAround:
+			spacing = free / len(line)
+			main += spacing / 2
+		}
+
+		lineSize := MaxInt(MinInt(crossSizes[l], crossStart+crossLength-cross), 0)
+		for i, item := range line {
+			align := item.Align
+			if align == FlexAlignAuto {
+				align = self.Align
+			}
+			itemCross, itemSize := cross, MinInt(item.CrossSize, lineSize)
+			switch align {
+			case FlexAlignAuto, FlexAlignStretch:
+				itemSize = lineSize
+			case FlexAlignEnd:
+				itemCross += lineSize - itemSize
+			case FlexAlignCenter:
+				itemCross += (lineSize - itemSize) / 2
+			}
+			rect := self.rect(main, itemCross, sizes[i], MaxInt(itemSize, 0))
+			item.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+			main += sizes[i] + self.Gap + spacing
+		}
+		cross += lineSize + self.Gap
+	}
+}
+
+// PreferredSize implements the Sizer interface: the items on a single line at their Basis size.
+func (self *Flex) PreferredSize(width int) image.Point {
+	width = self.InnerWidth(width)
+	self.resize(image.Pt(width, 0))
+	main, cross := 0, 0
+	for i, item := range self.Items {
+		if i > 0 {
+			main += self.Gap
+		}
+		main += item.Basis
+		cross = MaxInt(cross, item.CrossSize)
+	}
+	size := self.rect(0, 0, main, cross).Size()
+	return self.OuterSize(size)
+}
+
+func (self *Flex) Draw(buf *Buffer) {
+	self.Block.Draw(buf)
+	self.layout()
+	for _, item := range self.Items {
+		item.Lock()
+		item.Draw(buf.Sub(item.GetRect()))
+		item.Unlock()
+	}
+}
+
+// HitTest returns the topmost item of the Flex containing the point, as laid out by the last Draw.
+func (self *Flex) HitTest(point image.Point) Drawable {
+	for i := len(self.Items) - 1; i >= 0; i-- {
+		if point.In(self.Items[i].GetRect()) {
+			return self.Items[i].Drawable
+		}
+	}
+	return nil
+}
+
+// Focusables returns the Focusable items of the Flex, including the items of nested containers.
+func (self *Flex) Focusables() []Focusable {
+	drawables := make([]Drawable, len(self.Items))
+	for i, item := range self.Items {
+		drawables[i] = item.Drawable
+	}
+	return focusables(drawables)
+}
@@ -0,0 +1,266 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"image"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// sizedBlock is a Block implementing Sizer with a fixed preferred size.
+type sizedBlock struct {
+	Block
+	size image.Point
+}
+
+func (self *sizedBlock) PreferredSize(width int) image.Point {
+	return self.size
+}
+
+func newSizedBlock(width, height int) *sizedBlock {
+	return &sizedBlock{Block: *NewBlock(), size: image.Pt(width, height)}
+}
+
+// layoutFlex draws the Flex in a width x height area and returns the rects of its items.
+func layoutFlex(flex *Flex, width, height int) []image.Rectangle {
+	flex.SetRect(0, 0, width, height)
+	flex.Draw(NewBuffer(flex.Rectangle))
+	rects := make([]image.Rectangle, len(flex.Items))
+	for i, item := range flex.Items {
+		rects[i] = item.GetRect()
+	}
+	return rects
+}
+
+func TestFlexBasis(t *testing.T) {
+	flex := NewFlex(FlexRow)
+	flex.Add(NewBlock(), 5, 0)
+	flex.Add(NewBlock(), 10, 0)
+	rects := layoutFlex(flex, 20, 3)
+	expected := []image.Rectangle{image.Rect(0, 0, 5, 3), image.Rect(5, 0, 15, 3)}
+	for i := range expected {
+		if rects[i] != expected[i] {
+			t.Errorf("item %d: expected %v, got %v", i, expected[i], rects[i])
+		}
+	}
+}
+
+func TestFlexBasisFromRect(t *testing.T) {
+	block := NewBlock()
+	block.SetRect(0, 0, 6, 2)
+	flex := NewFlex(FlexRow)
+	flex.Align = FlexAlignStart
+	item := flex.Add(block, 0, 0)
+	if item.Auto {
+		t.Error("a Block without a preferred size shouldn't be sized automatically")
+	}
+	if item.Basis != 6 || item.CrossSize != 2 {
+		t.Errorf("expected basis 6 and cross size 2, got %d and %d", item.Basis, item.CrossSize)
+	}
+	if rect := layoutFlex(flex, 20, 5)[0]; rect != image.Rect(0, 0, 6, 2) {
+		t.Errorf("expected %v, got %v", image.Rect(0, 0, 6, 2), rect)
+	}
+}
+
+func TestFlexAuto(t *testing.T) {
+	flex := NewFlex(FlexColumn)
+	flex.Align = FlexAlignStart
+	item := flex.Add(newSizedBlock(7, 3), 0, 0)
+	if !item.Auto {
+		t.Fatal("a Sizer should be sized automatically")
+	}
+	if rect := layoutFlex(flex, 20, 10)[0]; rect != image.Rect(0, 0, 7, 3) {
+		t.Errorf("expected %v, got %v", image.Rect(0, 0, 7, 3), rect)
+	}
+	if size := flex.PreferredSize(20); size != image.Pt(7, 3) {
+		t.Errorf("expected preferred size %v, got %v", image.Pt(7, 3), size)
+	}
+}
+
+func TestFlexMainSizes(t *testing.T) {
+	type flexItem struct {
+		basis          int
+		grow, shrink   float64
+		expectedLength int
+	}
+	testCases := []struct {
+		name   string
+		length int
+		gap    int
+		items  []flexItem
+	}{
+		{"grow", 20, 0, []flexItem{{4, 1, 1, 7}, {4, 3, 1, 13}}},
+		{"grow one", 20, 0, []flexItem{{4, 0, 1, 4}, {4, 1, 1, 16}}},
+		{"grow rounding", 10, 0, []flexItem{{0, 1, 1, 3}, {0, 1, 1, 3}, {0, 1, 1, 4}}},
+		{"grow with gap", 12, 1, []flexItem{{0, 1, 1, 5}, {0, 1, 1, 6}}},
+		{"shrink", 10, 0, []flexItem{{10, 0, 1, 5}, {10, 0, 1, 5}}},
+		{"shrink by basis", 8, 0, []flexItem{{12, 0, 1, 6}, {4, 0, 1, 2}}},
+		{"shrink weights", 10, 0, []flexItem{{10, 0, 3, 2}, {10, 0, 1, 8}}},
+		{"no shrink", 10, 0, []flexItem{{8, 0, 0, 8}, {8, 0, 0, 8}}},
+	}
+	for _, tc := range testCases {
+		flex := NewFlex(FlexRow)
+		flex.Gap = tc.gap
+		for _, item := range tc.items {
+			flex.Add(NewBlock(), item.basis, item.grow).Shrink = item.shrink
+		}
+		rects := layoutFlex(flex, tc.length, 1)
+		for i, item := range tc.items {
+			if rects[i].Dx() != item.expectedLength {
+				t.Errorf("%s: item %d: expected length %d, got %d", tc.name, i, item.expectedLength, rects[i].Dx())
+			}
+		}
+	}
+}
+
+func expectRects(t *testing.T, name string, rects, expected []image.Rectangle) {
+	t.Helper()
+	for i := range expected {
+		if rects[i] != expected[i] {
+			t.Errorf("%s: item %d: expected %v, got %v", name, i, expected[i], rects[i])
+		}
+	}
+}
+
+// newRectBlock returns a Block whose rect gives its Basis and CrossSize in a Flex.
+func newRectBlock(width, height int) *Block {
+	block := NewBlock()
+	block.SetRect(0, 0, width, height)
+	return block
+}
+
+func TestFlexJustify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		justify  FlexJustify
+		expected [2]int
+	}{
+		{"start", FlexJustifyStart, [2]int{0, 4}},
+		{"end", FlexJustifyEnd, [2]int{12, 16}},
+		{"center", FlexJustifyCenter, [2]int{6, 10}},
+		{"space between", FlexJustifySpaceBetween, [2]int{0, 16}},
+		{"space around", FlexJustifySpaceAround, [2]int{3, 13}},
+	}
+	for _, tc := range testCases {
+		flex := NewFlex(FlexRow)
+		flex.Justify = tc.justify
+		flex.Add(NewBlock(), 4, 0)
+		flex.Add(NewBlock(), 4, 0)
+		rects := layoutFlex(flex, 20, 2)
+		expectRects(t, tc.name, rects, []image.Rectangle{
+			image.Rect(tc.expected[0], 0, tc.expected[0]+4, 2),
+			image.Rect(tc.expected[1], 0, tc.expected[1]+4, 2),
+		})
+	}
+}
+
+func TestFlexJustifyColumn(t *testing.T) {
+	flex := NewFlex(FlexColumn)
+	flex.Justify = FlexJustifyEnd
+	flex.Add(NewBlock(), 2, 0)
+	flex.Add(NewBlock(), 3, 0)
+	expectRects(t, "column end", layoutFlex(flex, 4, 10), []image.Rectangle{
+		image.Rect(0, 5, 4, 7),
+		image.Rect(0, 7, 4, 10),
+	})
+}
+
+func TestFlexAlign(t *testing.T) {
+	testCases := []struct {
+		name     string
+		align    FlexAlign
+		expected image.Rectangle
+	}{
+		{"auto", FlexAlignAuto, image.Rect(0, 0, 4, 6)},
+		{"stretch", FlexAlignStretch, image.Rect(0, 0, 4, 6)},
+		{"start", FlexAlignStart, image.Rect(0, 0, 4, 2)},
+		{"end", FlexAlignEnd, image.Rect(0, 4, 4, 6)},
+		{"center", FlexAlignCenter, image.Rect(0, 2, 4, 4)},
+	}
+	for _, tc := range testCases {
+		flex := NewFlex(FlexRow)
+		flex.Align = tc.align
+		flex.Add(newRectBlock(4, 2), 0, 0)
+		expectRects(t, tc.name, layoutFlex(flex, 20, 6), []image.Rectangle{tc.expected})
+
+		// the Align of an item overrides the Align of the Flex
+		flex = NewFlex(FlexRow)
+		flex.Align = FlexAlignEnd
+		flex.Add(newRectBlock(4, 2), 0, 0).Align = tc.align
+		if tc.align == FlexAlignAuto {
+			tc.expected = image.Rect(0, 4, 4, 6)
+		}
+		expectRects(t, tc.name+" item", layoutFlex(flex, 20, 6), []image.Rectangle{tc.expected})
+	}
+}
+
+func TestFlexWrap(t *testing.T) {
+	flex := NewFlex(FlexRow)
+	flex.Wrap = true
+	flex.Gap = 1
+	flex.Add(newRectBlock(4, 2), 0, 0)
+	flex.Add(newRectBlock(4, 3), 0, 0)
+	flex.Add(newRectBlock(4, 1), 0, 0)
+	flex.Add(newRectBlock(4, 2), 0, 0)
+	// each line is as high as its highest item
+	expectRects(t, "stretch", layoutFlex(flex, 10, 10), []image.Rectangle{
+		image.Rect(0, 0, 4, 3),
+		image.Rect(5, 0, 9, 3),
+		image.Rect(0, 4, 4, 6),
+		image.Rect(5, 4, 9, 6),
+	})
+
+	flex.Align = FlexAlignStart
+	expectRects(t, "start", layoutFlex(flex, 10, 10), []image.Rectangle{
+		image.Rect(0, 0, 4, 2),
+		image.Rect(5, 0, 9, 3),
+		image.Rect(0, 4, 4, 5),
+		image.Rect(5, 4, 9, 6),
+	})
+
+	// the lines are clipped to the Flex
+	flex.Align = FlexAlignStretch
+	expectRects(t, "clipped", layoutFlex(flex, 10, 5), []image.Rectangle{
+		image.Rect(0, 0, 4, 3),
+		image.Rect(5, 0, 9, 3),
+		image.Rect(0, 4, 4, 5),
+		image.Rect(5, 4, 9, 5),
+	})
+}
+
+func TestFlexInGrid(t *testing.T) {
+	flex := NewFlex(FlexRow)
+	flex.Add(NewBlock(), 3, 0)
+	flex.Add(NewBlock(), 0, 1)
+	grid := NewGrid()
+	grid.Set(NewRow(1, NewCol(0.5, NewBlock()), NewCol(0.5, flex)))
+	grid.SetRect(0, 0, 20, 4)
+	grid.Draw(NewBuffer(grid.Rectangle))
+
+	expectRects(t, "grid", []image.Rectangle{flex.Items[0].GetRect(), flex.Items[1].GetRect()}, []image.Rectangle{
+		image.Rect(10, 0, 13, 4),
+		image.Rect(13, 0, 20, 4),
+	})
+}
+
+func TestFlexInFlex(t *testing.T) {
+	inner := NewFlex(FlexRow)
+	inner.Add(NewBlock(), 3, 0)
+	inner.Add(NewBlock(), 0, 1)
+	outer := NewFlex(FlexColumn)
+	outer.Add(NewBlock(), 2, 0)
+	outer.Add(inner, 0, 1)
+
+	expectRects(t, "outer", layoutFlex(outer, 20, 6), []image.Rectangle{
+		image.Rect(0, 0, 20, 2),
+		image.Rect(0, 2, 20, 6),
+	})
+	expectRects(t, "inner", []image.Rectangle{inner.Items[0].GetRect(), inner.Items[1].GetRect()}, []image.Rectangle{
+		image.Rect(0, 2, 3, 6),
+		image.Rect(3, 2, 20, 6),
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// Focusable is implemented by the widgets which can receive the keyboard focus.
+// Block implements SetFocused and Focused, changing its border style while focused.
+type Focusable interface {
+	Drawable
+	SetFocused(bool)
+	Focused() bool
+	// HandleEvent handles an event sent to the widget and reports whether it was used.
+	HandleEvent(Event) bool
+}
+
+// focusables returns the Focusable drawables, replacing containers like Grid or Flex
+// by their own Focusables.
+func focusables(drawables []Drawable) []Focusable {
+	result := []Focusable{}
+	for _, drawable := range drawables {
+		switch drawable := drawable.(type) {
+		case interface{ Focusables() []Focusable }:
+			result = append(result, drawable.Focusables()...)
+		case Focusable:
+			result = append(result, drawable)
+		}
+	}
+	return result
+}
+
+// FocusManager keeps track of the focused widget among a list of Focusables.
+// <Tab> and <S-<Tab>> move the focus to the next and previous widget, a left click
+// focuses the widget under the mouse, and other events are sent to the focused widget.
+// A widget using a mouse press receives the following drag and release events,
+// wherever the mouse is, until the button is released.
+type FocusManager struct {
+	// OnChange is called with the newly focused widget, which may be nil.
+	OnChange func(Focusable)
+
+	items   []Focusable
+	focused int
+	// captured is the widget which used the last mouse press, if the button wasn't released
+	captured Focusable
+}
+
+// NewFocusManager returns a FocusManager cycling through the items in the given order,
+// with the first item focused.
+// The items of a Grid or a Flex can be listed with their Focusables method.
+func NewFocusManager(items ...Focusable) *FocusManager {
+	self := &FocusManager{focused: -1}
+	self.SetItems(items...)
+	return self
+}
+
+// SetItems replaces the focusable items, keeping the focus on the focused item if it is still present,
+// and focusing the first item otherwise.
+func (self *FocusManager) SetItems(items ...Focusable) {
+	focused := self.Focused()
+	self.items = items
+	self.focused = -1
+	self.captured = nil
+	for i, item := range items {
+		if focused != nil && sameDrawable(item, focused) {
+			self.focused = i
+		}
+		item.SetFocused(i == self.focused)
+	}
+	if self.focused == -1 && len(items) > 0 {
+		self.focusIndex(0)
+	} else if self.focused == -1 && focused != nil && self.OnChange != nil {
+		self.OnChange(nil)
+	}
+}
+
+// Items returns the focusable items.
+func (self *FocusManager) Items() []Focusable {
+	return self.items
+}
+
+// Focused returns the focused widget, or nil if there is none.
+func (self *FocusManager) Focused() Focusable {
+	if self.focused < 0 || self.focused >= len(self.items) {
+		return nil
+	}
+	return self.items[self.focused]
+}
+
+// Focus moves the focus to the given item, if it is one of the items of the manager.
+func (self *FocusManager) Focus(item Focusable) {
+	for i, other := range self.items {
+		if sameDrawable(other, item) {
+			self.focusIndex(i)
+			return
+		}
+	}
+}
+
+func (self *FocusManager) focusIndex(i int) {
+	if i == self.focused {
+		return
+	}
+	if old := self.Focused(); old != nil {
+		old.SetFocused(false)
+	}
+	self.focused = i
+	focused := self.Focused()
+	if focused != nil {
+		focused.SetFocused(true)
+	}
+	if self.OnChange != nil {
+		self.OnChange(focused)
+	}
+}
+
+// Next focuses the next item, wrapping around to the first one.
+func (self *FocusManager) Next() {
+	if len(self.items) > 0 {
+		self.focusIndex((self.focused + 1) % len(self.items))
+	}
+}
+
+// Previous focuses the previous item, wrapping around to the last one.
+func (self *FocusManager) Previous() {
+	if len(self.items) > 0 {
+		self.focusIndex((self.focused - 1 + len(self.items)) % len(self.items))
+	}
+}
+
+// itemAt returns the index of the last item containing the point, or -1.
+func (self *FocusManager) itemAt(point image.Point) int {
+	for i := len(self.items) - 1; i >= 0; i-- {
+		if point.In(self.items[i].GetRect()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleEvent moves the focus on <Tab>, <S-<Tab>> and left clicks, sends mouse events
+// to the widget under the mouse and the other events to the focused widget.
+// It reports whether the event was used.
+func (self *FocusManager) HandleEvent(e Event) bool {
+	switch e.Type {
+	case KeyboardEvent:
+		switch e.ID {
+		case "<Tab>":
+			self.Next()
+			return true
+		case "<S-<Tab>>":
+			self.Previous()
+			return true
+		}
+	case MouseEvent:
+		m, _ := e.Payload.(Mouse)
+		if captured := self.captured; captured != nil && (m.Action == MouseActionDrag || m.Action == MouseActionRelease) {
+			if m.Action == MouseActionRelease {
+				self.captured = nil
+			}
+			return captured.HandleEvent(e)
+		}
+		i := self.itemAt(image.Pt(m.X, m.Y))
+		if i < 0 {
+			return false
+		}
+		clicked := m.Button == MouseButtonLeft && m.Action == MouseActionPress
+		if clicked {
+			self.focusIndex(i)
+		}
+		used := self.items[i].HandleEvent(e)
+		wheel := m.Button == MouseButtonWheelUp || m.Button == MouseButtonWheelDown
+		if used && m.Action == MouseActionPress && !wheel {
+			self.captured = self.items[i]
+		}
+		return used || clicked
+	}
+
+	if focused := self.Focused(); focused != nil {
+		return focused.HandleEvent(e)
+	}
+	return false
+}
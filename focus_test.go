@@ -0,0 +1,181 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// focusBlock records the events it receives and uses all of them.
+type focusBlock struct {
+	Block
+	events []string
+}
+
+func newFocusBlock(x1, y1, x2, y2 int) *focusBlock {
+	block := &focusBlock{Block: *NewBlock()}
+	block.SetRect(x1, y1, x2, y2)
+	return block
+}
+
+func (self *focusBlock) HandleEvent(e Event) bool {
+	if m, ok := e.Payload.(Mouse); ok {
+		self.events = append(self.events, fmt.Sprintf("%s %d,%d", e.ID, m.X, m.Y))
+	} else {
+		self.events = append(self.events, e.ID)
+	}
+	return true
+}
+
+func newTestFocusManager() (*FocusManager, []*focusBlock) {
+	blocks := []*focusBlock{
+		newFocusBlock(0, 0, 5, 3),
+		newFocusBlock(5, 0, 10, 3),
+		newFocusBlock(10, 0, 15, 3),
+	}
+	return NewFocusManager(blocks[0], blocks[1], blocks[2]), blocks
+}
+
+func expectFocused(t *testing.T, manager *FocusManager, blocks []*focusBlock, expected int) {
+	t.Helper()
+	if focused := manager.Focused(); focused != Focusable(blocks[expected]) {
+		t.Fatalf("expected block %d to be focused, got %v", expected, focused)
+	}
+	for i, block := range blocks {
+		if block.Focused() != (i == expected) {
+			t.Errorf("block %d: expected Focused() to be %v", i, i == expected)
+		}
+	}
+}
+
+func TestFocusManagerTab(t *testing.T) {
+	manager, blocks := newTestFocusManager()
+	changes := []Focusable{}
+	manager.OnChange = func(focused Focusable) {
+		changes = append(changes, focused)
+	}
+	expectFocused(t, manager, blocks, 0)
+
+	for _, step := range []struct {
+		id       string
+		expected int
+	}{
+		{"<Tab>", 1},
+		{"<Tab>", 2},
+		{"<Tab>", 0},
+		{"<S-<Tab>>", 2},
+		{"<S-<Tab>>", 1},
+	} {
+		if !manager.HandleEvent(keyEvent(step.id)) {
+			t.Errorf("%s: expected the event to be used", step.id)
+		}
+		expectFocused(t, manager, blocks, step.expected)
+	}
+	if len(changes) != 5 || changes[4] != Focusable(blocks[1]) {
+		t.Errorf("expected 5 OnChange calls ending with block 1, got %v", changes)
+	}
+
+	manager.HandleEvent(keyEvent("a"))
+	if !reflect.DeepEqual(blocks[1].events, []string{"a"}) || len(blocks[0].events) != 0 {
+		t.Errorf("expected the key to be sent to the focused block only, got %v and %v", blocks[1].events, blocks[0].events)
+	}
+}
+
+func TestFocusManagerClick(t *testing.T) {
+	manager, blocks := newTestFocusManager()
+
+	manager.HandleEvent(mouseEvent(12, 1, MouseActionPress))
+	expectFocused(t, manager, blocks, 2)
+	manager.HandleEvent(mouseEvent(12, 1, MouseActionRelease))
+
+	wheel := Event{Type: MouseEvent, ID: "<MouseWheelDown>", Payload: Mouse{X: 6, Y: 1, Button: MouseButtonWheelDown, Action: MouseActionPress}}
+	manager.HandleEvent(wheel)
+	expectFocused(t, manager, blocks, 2)
+	if !reflect.DeepEqual(blocks[1].events, []string{"<MouseWheelDown> 6,1"}) {
+		t.Errorf("expected the wheel event to be sent to the block under the mouse, got %v", blocks[1].events)
+	}
+
+	if manager.HandleEvent(mouseEvent(20, 1, MouseActionPress)) {
+		t.Error("expected a click outside the items to be unused")
+	}
+	expectFocused(t, manager, blocks, 2)
+}
+
+func TestFocusManagerMouseCapture(t *testing.T) {
+	manager, blocks := newTestFocusManager()
+
+	manager.HandleEvent(mouseEvent(1, 1, MouseActionPress))
+	manager.HandleEvent(mouseEvent(7, 1, MouseActionDrag))
+	manager.HandleEvent(mouseEvent(20, 5, MouseActionRelease))
+	manager.HandleEvent(mouseEvent(7, 1, MouseActionDrag))
+
+	expected := []string{"<MouseLeft> 1,1", "<MouseLeft> 7,1", "<MouseLeft> 20,5"}
+	if !reflect.DeepEqual(blocks[0].events, expected) {
+		t.Errorf("expected the drag and release to go to the pressed block, got %v", blocks[0].events)
+	}
+	if !reflect.DeepEqual(blocks[1].events, []string{"<MouseLeft> 7,1"}) {
+		t.Errorf("expected the drag after the release to go to the block under the mouse, got %v", blocks[1].events)
+	}
+	expectFocused(t, manager, blocks, 0)
+}
+
+func TestFocusManagerSetItems(t *testing.T) {
+	manager, blocks := newTestFocusManager()
+	manager.Next()
+	manager.Next()
+
+	other := newFocusBlock(0, 3, 5, 6)
+	manager.SetItems(other, blocks[2], blocks[0])
+	if manager.Focused() != Focusable(blocks[2]) || other.Focused() || blocks[0].Focused() {
+		t.Fatalf("expected the focus to stay on block 2, got %v", manager.Focused())
+	}
+	manager.Next()
+	expectFocused(t, manager, []*focusBlock{other, blocks[2], blocks[0]}, 2)
+
+	var changed Focusable = blocks[1]
+	manager.OnChange = func(focused Focusable) {
+		changed = focused
+	}
+	manager.SetItems(other, blocks[1])
+	if changed != Focusable(other) {
+		t.Errorf("expected the first item to be focused when the focused one is removed, got %v", changed)
+	}
+	manager.SetItems()
+	if changed != nil || manager.Focused() != nil {
+		t.Errorf("expected no focus without items, got %v", changed)
+	}
+}
+
+func TestFocusedBorderStyle(t *testing.T) {
+	manager, blocks := newTestFocusManager()
+	for _, block := range blocks {
+		block.BorderStyle = NewStyle(ColorWhite)
+		block.FocusedBorderStyle = NewStyle(ColorYellow)
+	}
+	borderStyle := func(block *focusBlock) Style {
+		buf := NewBuffer(block.GetRect())
+		block.Draw(buf)
+		return buf.GetCell(image.Pt(block.Min.X, block.Min.Y+1)).Style
+	}
+
+	if style := borderStyle(blocks[0]); style != blocks[0].FocusedBorderStyle {
+		t.Errorf("expected the focused border style, got %v", style)
+	}
+	if style := borderStyle(blocks[1]); style != blocks[1].BorderStyle {
+		t.Errorf("expected the border style, got %v", style)
+	}
+	manager.Next()
+	if style := borderStyle(blocks[0]); style != blocks[0].BorderStyle {
+		t.Errorf("expected the border style after losing the focus, got %v", style)
+	}
+	if style := borderStyle(blocks[1]); style != blocks[1].FocusedBorderStyle {
+		t.Errorf("expected the focused border style after gaining the focus, got %v", style)
+	}
+}
@@ -4,6 +4,11 @@
 
 package termui
 
+import (
+	"image"
+	"math"
+)
+
 type gridItemType uint
 
 const (
@@ -13,20 +18,51 @@ const (
 
 type Grid struct {
 	Block
+	// Items are the leaves of the grid, in the order they were given to Set.
 	Items []*GridItem
+	// Gutter is the number of cells left empty between adjacent rows and columns.
+	Gutter int
+	// CollapseBorders makes adjacent items share the line between them, drawn with
+	// junctions like ├ or ┼ where borders meet. It is meant to be used with a Gutter of 0.
+	CollapseBorders bool
+
+	root *gridNode
 }
 
 // GridItem represents either a Row or Column in a grid.
 // Holds sizing information and either an []GridItems or a widget.
+//
+// The size of an item along the axis of its parent is, by order of precedence:
+// Size cells if Size > 0, the preferred size of its widget if Auto is set, a share of the space
+// left by the other items proportional to Weight if Weight > 0, or the ratio given to NewRow
+// or NewCol of the space of its parent.
+// Min and Max bound the size of the item if they are > 0.
 type GridItem struct {
-	Type        gridItemType
+	Type gridItemType
+	// XRatio, YRatio, WidthRatio and HeightRatio are the position and size of the item
+	// relative to the grid, as laid out by the last Draw.
 	XRatio      float64
 	YRatio      float64
 	WidthRatio  float64
 	HeightRatio float64
 	Entry       interface{} // Entry.type == GridBufferer if IsLeaf else []GridItem
 	IsLeaf      bool
-	ratio       float64
+
+	Size int
+	// Auto sizes the item to the PreferredSize of its widget, which must implement Sizer.
+	Auto   bool
+	Weight float64
+	Min    int
+	Max    int
+
+	ratio    float64
+	autoSize int
+}
+
+// gridNode is a GridItem of the layout tree built by Set.
+type gridNode struct {
+	item     *GridItem
+	children []*gridNode
 }
 
 func NewGrid() *Grid {
@@ -37,124 +73,295 @@ func NewGrid() *Grid {
 	return g
 }
 
-// NewCol takes a height percentage and either a widget or a Row or Column
-func NewCol(ratio float64, i ...interface{}) GridItem {
+func newGridItem(t gridItemType, i []interface{}) GridItem {
 	_, ok := i[0].(Drawable)
-	entry := i[0]
+	var entry interface{} = i[0]
 	if !ok {
 		entry = i
 	}
 	return GridItem{
-		Type:   col,
+		Type:   t,
 		Entry:  entry,
 		IsLeaf: ok,
-		ratio:  ratio,
 	}
 }
 
+// NewCol takes a height percentage and either a widget or a Row or Column
+func NewCol(ratio float64, i ...interface{}) GridItem {
+	item := newGridItem(col, i)
+	item.ratio = ratio
+	return item
+}
+
 // NewRow takes a width percentage and either a widget or a Row or Column
 func NewRow(ratio float64, i ...interface{}) GridItem {
-	_, ok := i[0].(Drawable)
-	entry := i[0]
-	if !ok {
-		entry = i
+	item := newGridItem(row, i)
+	item.ratio = ratio
+	return item
+}
+
+// NewFixedCol takes a width in cells and either a widget or a Row or Column
+func NewFixedCol(size int, i ...interface{}) GridItem {
+	item := newGridItem(col, i)
+	item.Size = size
+	return item
+}
+
+// NewFixedRow takes a height in cells and either a widget or a Row or Column
+func NewFixedRow(size int, i ...interface{}) GridItem {
+	item := newGridItem(row, i)
+	item.Size = size
+	return item
+}
+
+// NewFlexCol takes a weight and either a widget or a Row or Column.
+// Flex columns share the width left by the other columns according to their weights.
+func NewFlexCol(weight float64, i ...interface{}) GridItem {
+	item := newGridItem(col, i)
+	item.Weight = weight
+	return item
+}
+
+// NewFlexRow takes a weight and either a widget or a Row or Column.
+// Flex rows share the height left by the other rows according to their weights.
+func NewFlexRow(weight float64, i ...interface{}) GridItem {
+	item := newGridItem(row, i)
+	item.Weight = weight
+	return item
+}
+
+// NewAutoCol takes a widget implementing Sizer, and makes the column as wide as the widget prefers.
+func NewAutoCol(i ...interface{}) GridItem {
+	item := newGridItem(col, i)
+	item.Auto = true
+	return item
+}
+
+// NewAutoRow takes a widget implementing Sizer, and makes the row as high as the widget prefers
+// given the width of the row, e.g. for a header, a tab bar or a paragraph.
+func NewAutoRow(i ...interface{}) GridItem {
+	item := newGridItem(row, i)
+	item.Auto = true
+	return item
+}
+
+// WithMin returns a copy of the item with a minimum size in cells.
+func (self GridItem) WithMin(min int) GridItem {
+	self.Min = min
+	return self
+}
+
+// WithMax returns a copy of the item with a maximum size in cells.
+func (self GridItem) WithMax(max int) GridItem {
+	self.Max = max
+	return self
+}
+
+// clamp bounds a size by the Min and Max of the item.
+func (self *GridItem) clamp(size float64) float64 {
+	if self.Max > 0 && size > float64(self.Max) {
+		size = float64(self.Max)
 	}
-	return GridItem{
-		Type:   row,
-		Entry:  entry,
-		IsLeaf: ok,
-		ratio:  ratio,
+	if self.Min > 0 && size < float64(self.Min) {
+		size = float64(self.Min)
 	}
+	return size
 }
 
-// Set is used to add Columns and Rows to the grid.
-// It recursively searches the GridItems, adding leaves to the grid and calculating the dimensions of the leaves.
+// Set is used to add Columns and Rows to the grid, replacing the previous ones.
+// The children of a Row or Column are laid out side by side if they are Columns and stacked
+// if they are Rows. If both are mixed, they are laid out along the axis of the first child.
 func (self *Grid) Set(entries ...interface{}) {
-	entry := GridItem{
+	self.Items = nil
+	self.root = self.setHelper(&GridItem{
 		Type:   row,
 		Entry:  entries,
 		IsLeaf: false,
 		ratio:  1.0,
+	})
+}
+
+// setHelper builds the layout tree of an item, adding leaves to the grid.
+func (self *Grid) setHelper(item *GridItem) *gridNode {
+	node := &gridNode{item: item}
+	if item.IsLeaf {
+		self.Items = append(self.Items, item)
+		return node
 	}
-	self.setHelper(entry, 1.0, 1.0)
+	for _, child := range InterfaceSlice(item.Entry) {
+		if child, ok := child.(GridItem); ok {
+			node.children = append(node.children, self.setHelper(&child))
+		}
+	}
+	return node
 }
 
-func (self *Grid) setHelper(item GridItem, parentWidthRatio, parentHeightRatio float64) {
-	var HeightRatio float64
-	var WidthRatio float64
-	switch item.Type {
-	case col:
-		HeightRatio = 1.0
-		WidthRatio = item.ratio
-	case row:
-		HeightRatio = item.ratio
-		WidthRatio = 1.0
+// gridSizes computes the sizes of items sharing length cells separated by gutter cells.
+func gridSizes(items []*GridItem, length, gutter int) []int {
+	available := length - gutter*(len(items)-1)
+	if available < 0 {
+		available = 0
 	}
-	item.WidthRatio = parentWidthRatio * WidthRatio
-	item.HeightRatio = parentHeightRatio * HeightRatio
 
-	if item.IsLeaf {
-		self.Items = append(self.Items, &item)
-	} else {
-		XRatio := 0.0
-		YRatio := 0.0
-		cols := false
-		rows := false
-
-		children := InterfaceSlice(item.Entry)
-
-		for i := 0; i < len(children); i++ {
-			if children[i] == nil {
-				continue
+	sizes := make([]float64, len(items))
+	flex := []int{}
+	remaining := float64(available)
+	for i, item := range items {
+		switch {
+		case item.Size > 0:
+			sizes[i] = item.clamp(float64(item.Size))
+		case item.Auto:
+			sizes[i] = item.clamp(float64(item.autoSize))
+		case item.Weight > 0:
+			flex = append(flex, i)
+			continue
+		default:
+			sizes[i] = item.clamp(item.ratio * float64(available))
+		}
+		remaining -= sizes[i]
+	}
+
+	// share the remaining space between the flex items, setting aside the ones
+	// bounded by their Min or Max until the others fit
+	for len(flex) > 0 {
+		weights := 0.0
+		for _, i := range flex {
+			weights += items[i].Weight
+		}
+		space := math.Max(remaining, 0)
+		unbounded := []int{}
+		for _, i := range flex {
+			share := space * items[i].Weight / weights
+			if bounded := items[i].clamp(share); bounded != share {
+				sizes[i] = bounded
+				remaining -= bounded
+			} else {
+				unbounded = append(unbounded, i)
 			}
-			child, _ := children[i].(GridItem)
-
-			child.XRatio = item.XRatio + (item.WidthRatio * XRatio)
-			child.YRatio = item.YRatio + (item.HeightRatio * YRatio)
-
-			switch child.Type {
-			case col:
-				cols = true
-				XRatio += child.ratio
-				if rows {
-					item.HeightRatio /= 2
-				}
-			case row:
-				rows = true
-				YRatio += child.ratio
-				if cols {
-					item.WidthRatio /= 2
-				}
+		}
+		if len(unbounded) == len(flex) {
+			for _, i := range flex {
+				sizes[i] = space * items[i].Weight / weights
 			}
-
-			self.setHelper(child, item.WidthRatio, item.HeightRatio)
+			break
 		}
+		flex = unbounded
 	}
+
+	// round cumulative sizes so that items tile without gaps, cutting the ones which don't fit
+	result := make([]int, len(items))
+	total := 0.0
+	previous := 0
+	for i, size := range sizes {
+		total += size
+		end := MinInt(int(math.Floor(total+1e-6)), available)
+		result[i] = MaxInt(end-previous, 0)
+		previous = MaxInt(end, previous)
+	}
+	return result
 }
 
-func (self *Grid) Draw(buf *Buffer) {
-	width := float64(self.Dx()) + 1
-	height := float64(self.Dy()) + 1
+// layout sets the rects of the leaves of a node laid out in rect.
+func (self *Grid) layout(node *gridNode, rect image.Rectangle) {
+	item := node.item
+	if item.IsLeaf {
+		width, height := float64(MaxInt(self.Dx(), 1)), float64(MaxInt(self.Dy(), 1))
+		item.XRatio = float64(rect.Min.X-self.Min.X) / width
+		item.YRatio = float64(rect.Min.Y-self.Min.Y) / height
+		item.WidthRatio = float64(rect.Dx()) / width
+		item.HeightRatio = float64(rect.Dy()) / height
 
-	for _, item := range self.Items {
-		entry, _ := item.Entry.(Drawable)
+		// items overlap their right and bottom neighbours by the shared border
+		if self.CollapseBorders {
+			if rect.Max.X < self.Max.X {
+				rect.Max.X++
+			}
+			if rect.Max.Y < self.Max.Y {
+				rect.Max.Y++
+			}
+		}
 
-		x := int(width*item.XRatio) + self.Min.X
-		y := int(height*item.YRatio) + self.Min.Y
-		w := int(width * item.WidthRatio)
-		h := int(height * item.HeightRatio)
+		entry, _ := item.Entry.(Drawable)
+		entry.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+		return
+	}
+	if len(node.children) == 0 {
+		return
+	}
 
-		if x+w > self.Dx() {
-			w--
+	vertical := node.children[0].item.Type == row
+	children := make([]*GridItem, len(node.children))
+	for i, child := range node.children {
+		children[i] = child.item
+		if sizer, ok := child.item.Entry.(Sizer); ok && child.item.Auto {
+			size := sizer.PreferredSize(rect.Dx())
+			child.item.autoSize = size.X
+			if vertical {
+				child.item.autoSize = size.Y
+			}
 		}
-		if y+h > self.Dy() {
-			h--
+	}
+	length := rect.Dx()
+	if vertical {
+		length = rect.Dy()
+	}
+	sizes := gridSizes(children, length, self.Gutter)
+
+	position := rect.Min.X
+	if vertical {
+		position = rect.Min.Y
+	}
+	for i, child := range node.children {
+		childRect := rect
+		if vertical {
+			childRect.Min.Y, childRect.Max.Y = position, position+sizes[i]
+		} else {
+			childRect.Min.X, childRect.Max.X = position, position+sizes[i]
 		}
+		self.layout(child, childRect)
+		position += sizes[i] + self.Gutter
+	}
+}
 
-		entry.SetRect(x, y, x+w, y+h)
+// Draw lays the items out and draws each of them clipped to its rect.
+func (self *Grid) Draw(buf *Buffer) {
+	if self.root == nil {
+		return
+	}
+	self.layout(self.root, self.Rectangle)
 
+	entries := make([]Drawable, 0, len(self.Items))
+	for _, item := range self.Items {
+		entry, _ := item.Entry.(Drawable)
 		entry.Lock()
-		entry.Draw(buf)
+		entry.Draw(buf.Sub(entry.GetRect()))
 		entry.Unlock()
+		entries = append(entries, entry)
+	}
+	if self.CollapseBorders {
+		mergeBorders(buf.Sub(self.Rectangle), entries)
+	}
+}
+
+// HitTest returns the topmost item of the grid containing the point, as laid out by the last Draw.
+func (self *Grid) HitTest(point image.Point) Drawable {
+	for i := len(self.Items) - 1; i >= 0; i-- {
+		entry, ok := self.Items[i].Entry.(Drawable)
+		if ok && point.In(entry.GetRect()) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Focusables returns the Focusable items of the grid, in the order they were given to Set.
+// The items of nested containers are included.
+func (self *Grid) Focusables() []Focusable {
+	drawables := []Drawable{}
+	for _, item := range self.Items {
+		if entry, ok := item.Entry.(Drawable); ok {
+			drawables = append(drawables, entry)
+		}
 	}
+	return focusables(drawables)
 }
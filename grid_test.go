@@ -0,0 +1,87 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestGridSizes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		items    []GridItem
+		length   int
+		gutter   int
+		expected []int
+	}{
+		{"no items", []GridItem{}, 10, 1, []int{}},
+		{"ratios", []GridItem{{ratio: 0.5}, {ratio: 0.5}}, 10, 0, []int{5, 5}},
+		{"ratio rounding", []GridItem{{ratio: 1.0 / 3}, {ratio: 1.0 / 3}, {ratio: 1.0 / 3}}, 10, 0, []int{3, 3, 4}},
+		{"ratios below one", []GridItem{{ratio: 0.25}, {ratio: 0.25}}, 10, 0, []int{2, 3}},
+		{"fixed and flex", []GridItem{{Size: 3}, {Weight: 1}}, 10, 0, []int{3, 7}},
+		{"weights", []GridItem{{Weight: 1}, {Weight: 2}}, 9, 0, []int{3, 6}},
+		{"weight rounding", []GridItem{{Weight: 1}, {Weight: 2}}, 10, 0, []int{3, 7}},
+		{"equal weight rounding", []GridItem{{Weight: 1}, {Weight: 1}, {Weight: 1}}, 8, 0, []int{2, 3, 3}},
+		{"gutter", []GridItem{{Weight: 1}, {Weight: 1}}, 11, 1, []int{5, 5}},
+		{"gutter and ratios", []GridItem{{ratio: 0.5}, {ratio: 0.5}}, 12, 2, []int{5, 5}},
+		{"gutter larger than length", []GridItem{{Weight: 1}, {Size: 2}, {ratio: 1}}, 1, 1, []int{0, 0, 0}},
+		{"zero length", []GridItem{{Weight: 1}, {Size: 2}}, 0, 0, []int{0, 0}},
+		{"auto", []GridItem{{Auto: true, autoSize: 4}, {Weight: 1}}, 10, 0, []int{4, 6}},
+		{"flex max", []GridItem{{Weight: 1, Max: 2}, {Weight: 1}}, 10, 0, []int{2, 8}},
+		{"flex min", []GridItem{{Weight: 1, Min: 8}, {Weight: 1}}, 10, 0, []int{8, 2}},
+		{"flex max cascade", []GridItem{{Weight: 1, Max: 1}, {Weight: 1, Max: 3}, {Weight: 2}}, 12, 0, []int{1, 3, 8}},
+		{"all flex bounded", []GridItem{{Weight: 1, Max: 2}, {Weight: 1, Max: 3}}, 10, 0, []int{2, 3}},
+		{"flex without space", []GridItem{{Size: 10}, {Weight: 1}, {Weight: 1}}, 10, 0, []int{10, 0, 0}},
+		{"fixed max", []GridItem{{Size: 8, Max: 5}, {Weight: 1}}, 10, 0, []int{5, 5}},
+		{"fixed min", []GridItem{{Size: 2, Min: 4}, {Weight: 1}}, 10, 0, []int{4, 6}},
+		{"ratio max", []GridItem{{ratio: 1, Max: 4}}, 10, 0, []int{4}},
+		{"auto min", []GridItem{{Auto: true, autoSize: 1, Min: 3}, {Weight: 1}}, 10, 0, []int{3, 7}},
+		{"overflow is cut", []GridItem{{Size: 6}, {Size: 6}, {Size: 6}}, 10, 0, []int{6, 4, 0}},
+		{"overflow with flex min", []GridItem{{Size: 8}, {Weight: 1, Min: 5}}, 10, 0, []int{8, 2}},
+	}
+	for _, tc := range testCases {
+		items := make([]*GridItem, len(tc.items))
+		for i := range tc.items {
+			items[i] = &tc.items[i]
+		}
+		sizes := gridSizes(items, tc.length, tc.gutter)
+		if !reflect.DeepEqual(sizes, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, sizes)
+		}
+	}
+}
+
+func TestGridLayout(t *testing.T) {
+	header, left, right := NewBlock(), NewBlock(), NewBlock()
+	grid := NewGrid()
+	grid.Gutter = 1
+	grid.SetRect(0, 0, 21, 11)
+	grid.Set(
+		NewFixedRow(3, header),
+		NewFlexRow(1,
+			NewCol(0.5, left),
+			NewCol(0.5, right),
+		),
+	)
+	grid.Draw(NewBuffer(grid.Rectangle))
+
+	expected := []image.Rectangle{
+		image.Rect(0, 0, 21, 3),
+		image.Rect(0, 4, 10, 11),
+		image.Rect(11, 4, 21, 11),
+	}
+	for i, block := range []*Block{header, left, right} {
+		if rect := block.GetRect(); rect != expected[i] {
+			t.Errorf("item %d: expected %v, got %v", i, expected[i], rect)
+		}
+	}
+
+	item := grid.Items[2]
+	if item.XRatio != 11.0/21 || item.YRatio != 4.0/11 || item.WidthRatio != 10.0/21 || item.HeightRatio != 7.0/11 {
+		t.Errorf("unexpected ratios %v, %v, %v, %v", item.XRatio, item.YRatio, item.WidthRatio, item.HeightRatio)
+	}
+}
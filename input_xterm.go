@@ -0,0 +1,216 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// xtermFinalKeys are the keys reported as `ESC [ <final>` or `ESC [ 1 ; <modifiers> <final>`.
+var xtermFinalKeys = map[byte]KeyCode{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+	'Z': KeyTab,
+}
+
+// xtermTildeKeys are the keys reported as `ESC [ <number> ~` or `ESC [ <number> ; <modifiers> ~`.
+var xtermTildeKeys = map[int]KeyCode{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// parseXtermKey parses the escape sequences xterm compatible terminals use to report
+// keys, including the ones combined with modifiers which termbox doesn't know about.
+// The modifiers (; m) are optional:
+//
+//	ESC [ 1 ; m A        arrows, Home, End and F1-F4
+//	ESC [ n ; m ~        Insert, Delete, PageUp, PageDown and function keys
+//	ESC [ Z              Shift+Tab
+//	ESC [ 27 ; m ; c ~   keys reported with modifyOtherKeys
+//	ESC [ c ; m u        keys reported with the CSI u protocol
+//
+// It returns the number of bytes used, or 0 if buf doesn't start with one of these sequences.
+// partial reports that buf could be the beginning of such a sequence.
+func parseXtermKey(buf []byte) (key Key, n int, partial bool) {
+	if len(buf) < 2 || buf[0] != '\x1b' || buf[1] != '[' {
+		return Key{}, 0, len(buf) == 1 && buf[0] == '\x1b'
+	}
+	end := 2
+	for end < len(buf) && (buf[end] >= '0' && buf[end] <= '9' || buf[end] == ';') {
+		end++
+	}
+	if end == len(buf) {
+		return Key{}, 0, true
+	}
+
+	final := buf[end]
+	var params []int
+	if end > 2 {
+		for _, s := range strings.Split(string(buf[2:end]), ";") {
+			param, err := strconv.Atoi(s)
+			if err != nil {
+				return Key{}, 0, false
+			}
+			params = append(params, param)
+		}
+	}
+
+	switch {
+	case final == 'Z' && len(params) == 0:
+		key = Key{Code: KeyTab, Mod: ModShift}
+	case (len(params) == 0 || len(params) == 2) && xtermFinalKeys[final] != KeyUnknown:
+		key = Key{Code: xtermFinalKeys[final]}
+	case final == '~' && len(params) == 3 && params[0] == 27:
+		key = xtermCodeKey(params[2])
+		params = params[:2]
+	case final == '~' && (len(params) == 1 || len(params) == 2) && xtermTildeKeys[params[0]] != KeyUnknown:
+		key = Key{Code: xtermTildeKeys[params[0]]}
+	case final == 'u' && len(params) >= 1:
+		key = xtermCodeKey(params[0])
+		params = append([]int{0}, params[1:]...)
+	default:
+		return Key{}, 0, false
+	}
+
+	if len(params) == 2 && params[1] > 1 {
+		key.Mod |= KeyModifier(params[1]-1) & (ModShift | ModAlt | ModCtrl)
+	}
+	if key.Code == KeyRune && key.Mod&ModShift != 0 {
+		// shift is already part of the rune
+		key.Rune = unicode.ToUpper(key.Rune)
+		key.Mod &^= ModShift
+	}
+	return key, end + 1, false
+}
+
+// xtermCodeKey returns the Key of a character code reported by modifyOtherKeys or CSI u.
+func xtermCodeKey(code int) Key {
+	switch code {
+	case 8, 127:
+		return Key{Code: KeyBackspace}
+	case 9:
+		return Key{Code: KeyTab}
+	case 13:
+		return Key{Code: KeyEnter}
+	case 27:
+		return Key{Code: KeyEscape}
+	case 32:
+		return Key{Code: KeySpace}
+	}
+	return Key{Code: KeyRune, Rune: rune(code)}
+}
+
+// parseXtermMouse parses mouse events reported with the SGR and X10 encodings,
+// including the modifiers termbox doesn't report:
+//
+//	ESC [ < b ; x ; y M   SGR press, drag or move, m for a release
+//	ESC [ M b x y         X10, with each value offset by 32
+//
+// It returns the number of bytes used, or 0 if buf doesn't start with one of these sequences.
+// partial reports that buf could be the beginning of such a sequence.
+func parseXtermMouse(buf []byte) (m Mouse, n int, partial bool) {
+	if len(buf) < 3 || buf[0] != '\x1b' || buf[1] != '[' {
+		return Mouse{}, 0, len(buf) > 0 && len(buf) < 3 && buf[0] == '\x1b'
+	}
+
+	if buf[2] == 'M' {
+		if len(buf) < 6 {
+			return Mouse{}, 0, true
+		}
+		m = xtermMouse(int(buf[3])-32, int(buf[4])-33, int(buf[5])-33, false)
+		return m, 6, false
+	}
+
+	if buf[2] != '<' {
+		return Mouse{}, 0, false
+	}
+	end := 3
+	for end < len(buf) && (buf[end] >= '0' && buf[end] <= '9' || buf[end] == ';') {
+		end++
+	}
+	if end == len(buf) {
+		return Mouse{}, 0, true
+	}
+	if buf[end] != 'M' && buf[end] != 'm' {
+		return Mouse{}, 0, false
+	}
+	var params []int
+	for _, s := range strings.Split(string(buf[3:end]), ";") {
+		param, err := strconv.Atoi(s)
+		if err != nil {
+			return Mouse{}, 0, false
+		}
+		params = append(params, param)
+	}
+	if len(params) != 3 {
+		return Mouse{}, 0, false
+	}
+	m = xtermMouse(params[0], params[1]-1, params[2]-1, buf[end] == 'm')
+	return m, end + 1, false
+}
+
+// xtermMouse decodes the button value of an xterm mouse event.
+func xtermMouse(b, x, y int, release bool) Mouse {
+	m := Mouse{X: x, Y: y}
+	if b&4 != 0 {
+		m.Mod |= ModShift
+	}
+	if b&8 != 0 {
+		m.Mod |= ModAlt
+	}
+	if b&16 != 0 {
+		m.Mod |= ModCtrl
+	}
+
+	switch {
+	case b&64 != 0:
+		m.Button = MouseButtonWheelUp + MouseButton(b&1)
+	case b&3 == 3:
+		// X10 doesn't report which button was released
+		m.Action = MouseActionRelease
+	default:
+		m.Button = MouseButtonLeft + MouseButton(b&3)
+	}
+
+	switch {
+	case m.Action == MouseActionRelease:
+		if b&32 != 0 {
+			m.Action = MouseActionMove
+		}
+	case release:
+		m.Action = MouseActionRelease
+	case b&32 != 0:
+		m.Action = MouseActionDrag
+	}
+	return m
+}
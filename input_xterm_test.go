@@ -0,0 +1,110 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+)
+
+func TestParseXtermKey(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Key
+		n        int
+		partial  bool
+	}{
+		{"\x1b[A", Key{Code: KeyUp}, 3, false},
+		{"\x1b[1;5A", Key{Code: KeyUp, Mod: ModCtrl}, 6, false},
+		{"\x1b[1;2D", Key{Code: KeyLeft, Mod: ModShift}, 6, false},
+		{"\x1b[1;8H", Key{Code: KeyHome, Mod: ModShift | ModAlt | ModCtrl}, 6, false},
+		{"\x1b[1;3Pq", Key{Code: KeyF1, Mod: ModAlt}, 6, false},
+		{"\x1b[Z", Key{Code: KeyTab, Mod: ModShift}, 3, false},
+		{"\x1b[2~", Key{Code: KeyInsert}, 4, false},
+		{"\x1b[3;2~", Key{Code: KeyDelete, Mod: ModShift}, 6, false},
+		{"\x1b[6;5~", Key{Code: KeyPageDown, Mod: ModCtrl}, 6, false},
+		{"\x1b[24~", Key{Code: KeyF12}, 5, false},
+		{"\x1b[15;1~", Key{Code: KeyF5}, 7, false},
+		// modifyOtherKeys
+		{"\x1b[27;5;104~", Key{Code: KeyRune, Rune: 'h', Mod: ModCtrl}, 11, false},
+		{"\x1b[27;5;13~", Key{Code: KeyEnter, Mod: ModCtrl}, 10, false},
+		{"\x1b[27;6;97~", Key{Code: KeyRune, Rune: 'A', Mod: ModCtrl}, 10, false},
+		// CSI u
+		{"\x1b[104;5u", Key{Code: KeyRune, Rune: 'h', Mod: ModCtrl}, 8, false},
+		{"\x1b[97;2u", Key{Code: KeyRune, Rune: 'A'}, 7, false},
+		{"\x1b[13;3u", Key{Code: KeyEnter, Mod: ModAlt}, 7, false},
+		{"\x1b[127u", Key{Code: KeyBackspace}, 6, false},
+		{"\x1b[32;5u", Key{Code: KeySpace, Mod: ModCtrl}, 7, false},
+		// partial sequences
+		{"\x1b", Key{}, 0, true},
+		{"\x1b[", Key{}, 0, true},
+		{"\x1b[1", Key{}, 0, true},
+		{"\x1b[1;5", Key{}, 0, true},
+		{"\x1b[27;5;104", Key{}, 0, true},
+		// other input
+		{"", Key{}, 0, false},
+		{"a", Key{}, 0, false},
+		{"\x1bx", Key{}, 0, false},
+		{"\x1b[99~", Key{}, 0, false},
+		{"\x1b[1;2;3A", Key{}, 0, false},
+		{"\x1b[1;aA", Key{}, 0, false},
+		{"\x1b[<0;1;1M", Key{}, 0, false},
+		{"\x1b[M !!", Key{}, 0, false},
+	}
+	for _, tc := range testCases {
+		key, n, partial := parseXtermKey([]byte(tc.input))
+		if key != tc.expected || n != tc.n || partial != tc.partial {
+			t.Errorf("%q: expected %+v, %d, %v, got %+v, %d, %v",
+				tc.input, tc.expected, tc.n, tc.partial, key, n, partial)
+		}
+	}
+}
+
+func TestParseXtermMouse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected Mouse
+		n        int
+		partial  bool
+	}{
+		// SGR
+		{"\x1b[<0;10;5M", Mouse{X: 9, Y: 4, Button: MouseButtonLeft}, 10, false},
+		{"\x1b[<0;10;5m", Mouse{X: 9, Y: 4, Button: MouseButtonLeft, Action: MouseActionRelease}, 10, false},
+		{"\x1b[<1;1;1M", Mouse{Button: MouseButtonMiddle}, 9, false},
+		{"\x1b[<2;1;1Mx", Mouse{Button: MouseButtonRight}, 9, false},
+		{"\x1b[<32;3;4M", Mouse{X: 2, Y: 3, Button: MouseButtonLeft, Action: MouseActionDrag}, 10, false},
+		{"\x1b[<35;3;4M", Mouse{X: 2, Y: 3, Action: MouseActionMove}, 10, false},
+		{"\x1b[<64;1;1M", Mouse{Button: MouseButtonWheelUp}, 10, false},
+		{"\x1b[<65;1;1M", Mouse{Button: MouseButtonWheelDown}, 10, false},
+		{"\x1b[<4;1;1M", Mouse{Button: MouseButtonLeft, Mod: ModShift}, 9, false},
+		{"\x1b[<24;1;1M", Mouse{Button: MouseButtonLeft, Mod: ModAlt | ModCtrl}, 10, false},
+		{"\x1b[<0;300;200M", Mouse{X: 299, Y: 199, Button: MouseButtonLeft}, 13, false},
+		// X10
+		{"\x1b[M *%", Mouse{X: 9, Y: 4, Button: MouseButtonLeft}, 6, false},
+		{"\x1b[M#!!", Mouse{Action: MouseActionRelease}, 6, false},
+		{"\x1b[M0!!", Mouse{Button: MouseButtonLeft, Mod: ModCtrl}, 6, false},
+		{"\x1b[M`!!", Mouse{Button: MouseButtonWheelUp}, 6, false},
+		// partial sequences
+		{"\x1b", Mouse{}, 0, true},
+		{"\x1b[", Mouse{}, 0, true},
+		{"\x1b[M", Mouse{}, 0, true},
+		{"\x1b[M !", Mouse{}, 0, true},
+		{"\x1b[<", Mouse{}, 0, true},
+		{"\x1b[<0;10", Mouse{}, 0, true},
+		// other input
+		{"", Mouse{}, 0, false},
+		{"abc", Mouse{}, 0, false},
+		{"\x1b[A", Mouse{}, 0, false},
+		{"\x1b[<0;1M", Mouse{}, 0, false},
+		{"\x1b[<0;1;1;1M", Mouse{}, 0, false},
+		{"\x1b[<0;1;1x", Mouse{}, 0, false},
+	}
+	for _, tc := range testCases {
+		m, n, partial := parseXtermMouse([]byte(tc.input))
+		if m != tc.expected || n != tc.n || partial != tc.partial {
+			t.Errorf("%q: expected %+v, %d, %v, got %+v, %d, %v",
+				tc.input, tc.expected, tc.n, tc.partial, m, n, partial)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyCode identifies a key of the keyboard. Printable characters use KeyRune.
+type KeyCode uint
+
+const (
+	KeyUnknown KeyCode = iota
+	KeyRune
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyInsert
+	KeyDelete
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyBackspace
+	KeyTab
+	KeyEnter
+	KeyEscape
+	KeySpace
+)
+
+var keyNames = map[KeyCode]string{
+	KeyF1:        "F1",
+	KeyF2:        "F2",
+	KeyF3:        "F3",
+	KeyF4:        "F4",
+	KeyF5:        "F5",
+	KeyF6:        "F6",
+	KeyF7:        "F7",
+	KeyF8:        "F8",
+	KeyF9:        "F9",
+	KeyF10:       "F10",
+	KeyF11:       "F11",
+	KeyF12:       "F12",
+	KeyInsert:    "Insert",
+	KeyDelete:    "Delete",
+	KeyHome:      "Home",
+	KeyEnd:       "End",
+	KeyPageUp:    "PageUp",
+	KeyPageDown:  "PageDown",
+	KeyUp:        "Up",
+	KeyDown:      "Down",
+	KeyLeft:      "Left",
+	KeyRight:     "Right",
+	KeyBackspace: "Backspace",
+	KeyTab:       "Tab",
+	KeyEnter:     "Enter",
+	KeyEscape:    "Escape",
+	KeySpace:     "Space",
+}
+
+// KeyModifier is a set of modifier keys held down with a key.
+type KeyModifier uint
+
+const (
+	ModShift KeyModifier = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+// keyModifierPrefixes are the prefixes of modifiers in event IDs, in order.
+// Key.String puts Alt around the other modifiers instead.
+var keyModifierPrefixes = []struct {
+	mod    KeyModifier
+	prefix string
+}{
+	{ModCtrl, "C-"},
+	{ModAlt, "M-"},
+	{ModShift, "S-"},
+}
+
+// Key payload.
+// Shift is only reported for keys which don't produce a character, as it is already
+// applied to Rune otherwise.
+type Key struct {
+	Code KeyCode
+	Rune rune
+	Mod  KeyModifier
+}
+
+// String returns the event ID of the Key, e.g. `j`, `<Up>`, `<C-d>`, `<M-<Up>>` or `<C-S-<Left>>`.
+// Alt wraps the ID of the key pressed without it, e.g. `<M-<C-a>>`.
+// Unknown keys return an empty string.
+func (self Key) String() string {
+	if self.Mod&ModAlt != 0 {
+		key := self
+		key.Mod &^= ModAlt
+		if id := key.String(); id != "" {
+			return "<M-" + id + ">"
+		}
+		return ""
+	}
+
+	var base string
+	switch self.Code {
+	case KeyUnknown:
+		return ""
+	case KeyRune:
+		base = string(self.Rune)
+	default:
+		base = "<" + keyNames[self.Code] + ">"
+	}
+
+	prefix := ""
+	for _, p := range keyModifierPrefixes {
+		if self.Mod&p.mod != 0 {
+			prefix += p.prefix
+		}
+	}
+	if prefix == "" {
+		return base
+	}
+	return "<" + prefix + base + ">"
+}
+
+// ParseKey parses an event ID as returned by Key.String.
+// Alt can also be combined with the other modifiers, e.g. `<C-M-a>` for `<M-<C-a>>`.
+func ParseKey(id string) (Key, bool) {
+	var key Key
+	for strings.HasPrefix(id, "<") && strings.HasSuffix(id, ">") && len(id) > 2 {
+		inner := id[1 : len(id)-1]
+		found := false
+		for _, p := range keyModifierPrefixes {
+			if strings.HasPrefix(inner, p.prefix) && len(inner) > len(p.prefix) {
+				key.Mod |= p.mod
+				inner = inner[len(p.prefix):]
+				found = true
+			}
+		}
+		if !found {
+			for code, name := range keyNames {
+				if inner == name {
+					key.Code = code
+					return key, true
+				}
+			}
+			return Key{}, false
+		}
+		id = inner
+	}
+	if r, size := utf8.DecodeRuneInString(id); size == len(id) && r != utf8.RuneError {
+		key.Code = KeyRune
+		key.Rune = r
+		return key, true
+	}
+	return Key{}, false
+}
+
+// newKeyboardEvent returns the KeyboardEvent of a Key.
+func newKeyboardEvent(key Key) Event {
+	return Event{
+		Type:    KeyboardEvent,
+		ID:      key.String(),
+		Payload: key,
+	}
+}
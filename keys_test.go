@@ -0,0 +1,85 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		id       string
+		expected Key
+		ok       bool
+	}{
+		{"j", Key{Code: KeyRune, Rune: 'j'}, true},
+		{"J", Key{Code: KeyRune, Rune: 'J'}, true},
+		{"é", Key{Code: KeyRune, Rune: 'é'}, true},
+		{"<", Key{Code: KeyRune, Rune: '<'}, true},
+		{">", Key{Code: KeyRune, Rune: '>'}, true},
+		{"<Up>", Key{Code: KeyUp}, true},
+		{"<Space>", Key{Code: KeySpace}, true},
+		{"<F12>", Key{Code: KeyF12}, true},
+		{"<C-d>", Key{Code: KeyRune, Rune: 'd', Mod: ModCtrl}, true},
+		{"<C-->", Key{Code: KeyRune, Rune: '-', Mod: ModCtrl}, true},
+		{"<M-<Up>>", Key{Code: KeyUp, Mod: ModAlt}, true},
+		{"<S-<Tab>>", Key{Code: KeyTab, Mod: ModShift}, true},
+		{"<C-M-S-<Left>>", Key{Code: KeyLeft, Mod: ModCtrl | ModAlt | ModShift}, true},
+		{"<C-M-x>", Key{Code: KeyRune, Rune: 'x', Mod: ModCtrl | ModAlt}, true},
+		{"<M-<C-x>>", Key{Code: KeyRune, Rune: 'x', Mod: ModCtrl | ModAlt}, true},
+		{"<M-<C-S-<Left>>>", Key{Code: KeyLeft, Mod: ModCtrl | ModAlt | ModShift}, true},
+		{"", Key{}, false},
+		{"<>", Key{}, false},
+		{"ab", Key{}, false},
+		{"<Foo>", Key{}, false},
+		{"<C->", Key{}, false},
+		{"<C-ab>", Key{}, false},
+		{"<S-C-<Up>>", Key{}, false},
+	}
+	for _, tc := range testCases {
+		key, ok := ParseKey(tc.id)
+		if key != tc.expected || ok != tc.ok {
+			t.Errorf("%q: expected %+v, %v, got %+v, %v", tc.id, tc.expected, tc.ok, key, ok)
+		}
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	testCases := []struct {
+		key      Key
+		expected string
+	}{
+		{Key{}, ""},
+		{Key{Code: KeyRune, Rune: 'q'}, "q"},
+		{Key{Code: KeyEnter}, "<Enter>"},
+		{Key{Code: KeyRune, Rune: 'c', Mod: ModCtrl}, "<C-c>"},
+		{Key{Code: KeyUp, Mod: ModShift | ModCtrl}, "<C-S-<Up>>"},
+		{Key{Code: KeyRune, Rune: 'x', Mod: ModAlt}, "<M-x>"},
+		{Key{Code: KeyRune, Rune: 'a', Mod: ModAlt | ModCtrl}, "<M-<C-a>>"},
+		{Key{Code: KeyPageDown, Mod: ModAlt | ModShift}, "<M-<S-<PageDown>>>"},
+		{Key{Code: KeyUnknown, Mod: ModAlt}, ""},
+	}
+	for _, tc := range testCases {
+		if s := tc.key.String(); s != tc.expected {
+			t.Errorf("%+v: expected %q, got %q", tc.key, tc.expected, s)
+		}
+	}
+}
+
+func TestParseKeyRoundTrip(t *testing.T) {
+	keys := []Key{{Code: KeyRune, Rune: 'a'}, {Code: KeyRune, Rune: '<'}}
+	for code := range keyNames {
+		keys = append(keys, Key{Code: code})
+	}
+	for _, key := range keys {
+		for mod := KeyModifier(0); mod <= ModShift|ModAlt|ModCtrl; mod++ {
+			key.Mod = mod
+			parsed, ok := ParseKey(key.String())
+			if !ok || parsed != key {
+				t.Errorf("%q: expected %+v, got %+v, %v", key.String(), key, parsed, ok)
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// Layer is the z-order of the items of a Layers.
+type Layer uint
+
+const (
+	// LayerBase holds the items filling the Layers, like a Grid.
+	LayerBase Layer = iota
+	// LayerOverlay holds popups drawn above the base items, which keep their own rect.
+	LayerOverlay
+	// LayerModal holds dialogs drawn above everything else and capturing the input.
+	LayerModal
+)
+
+const layerCount = int(LayerModal) + 1
+
+// Layers draws its items by layer, base items first, so that overlays and modals
+// stay on top of the base items however often those are redrawn.
+//
+// Base items are resized with the Layers. Modals implementing Sizer are centered
+// at their preferred size on every Draw, the other ones keep the rect they were given,
+// and the cells below the topmost modal are dimmed with Backdrop. While a modal is open, it receives the events and <Escape> closes it.
+type Layers struct {
+	Block
+	// Backdrop is applied to the cells below the topmost modal: its modifiers are added
+	// to theirs, and its colors replace theirs unless they are ColorClear.
+	Backdrop Style
+	// Shade, if not 0, replaces the runes below the topmost modal, e.g. SHADED_BLOCKS[1].
+	Shade rune
+	// OnClose is called with a modal closed by <Escape> or CloseModal.
+	OnClose func(Drawable)
+
+	items [layerCount][]Drawable
+}
+
+// NewLayers returns a Layers holding the given base items.
+func NewLayers(base ...Drawable) *Layers {
+	l := &Layers{
+		Block:    *NewBlock(),
+		Backdrop: Theme.Layers.Backdrop,
+	}
+	l.Border = false
+	for _, item := range base {
+		l.Push(LayerBase, item)
+	}
+	return l
+}
+
+// Push adds an item on top of a layer, moving it there if it was already in the Layers.
+// Base items are given the rect of the Layers.
+func (self *Layers) Push(layer Layer, item Drawable) {
+	self.Remove(item)
+	self.items[layer] = append(self.items[layer], item)
+	if layer == LayerBase {
+		item.Lock()
+		item.SetRect(self.Min.X, self.Min.Y, self.Max.X, self.Max.Y)
+		item.Unlock()
+	}
+}
+
+// Remove removes an item from its layer. It reports whether the item was found.
+func (self *Layers) Remove(item Drawable) bool {
+	for layer, items := range self.items {
+		for i, other := range items {
+			if sameDrawable(other, item) {
+				self.items[layer] = append(items[:i:i], items[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Items returns the items of a layer, from bottom to top.
+func (self *Layers) Items(layer Layer) []Drawable {
+	return self.items[layer]
+}
+
+// Modal returns the topmost modal, or nil if there is none.
+func (self *Layers) Modal() Drawable {
+	modals := self.items[LayerModal]
+	if len(modals) == 0 {
+		return nil
+	}
+	return modals[len(modals)-1]
+}
+
+// CloseModal removes the topmost modal and calls OnClose with it.
+func (self *Layers) CloseModal() {
+	modal := self.Modal()
+	if modal == nil {
+		return
+	}
+	self.Remove(modal)
+	if self.OnClose != nil {
+		self.OnClose(modal)
+	}
+}
+
+func (self *Layers) SetRect(x1, y1, x2, y2 int) {
+	self.Block.SetRect(x1, y1, x2, y2)
+	for _, item := range self.items[LayerBase] {
+		item.Lock()
+		item.SetRect(x1, y1, x2, y2)
+		item.Unlock()
+	}
+}
+
+// center sets the rect of a modal implementing Sizer to its preferred size,
+// centered in the Layers. Modals without a preferred size keep their rect.
+func (self *Layers) center(item Drawable) {
+	sizer, ok := item.(Sizer)
+	if !ok {
+		return
+	}
+	size := sizer.PreferredSize(self.Dx())
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+	size.X, size.Y = MinInt(size.X, self.Dx()), MinInt(size.Y, self.Dy())
+	min := self.Min.Add(self.Size().Sub(size).Div(2))
+	item.SetRect(min.X, min.Y, min.X+size.X, min.Y+size.Y)
+}
+
+// dim applies the Backdrop and Shade to the cells of the buffer inside the Layers.
+func (self *Layers) dim(buf *Buffer) {
+	rect := self.Intersect(buf.Rectangle)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			point := image.Pt(x, y)
+			cell := buf.GetCell(point)
+			if self.Shade != 0 {
+				cell.Rune = self.Shade
+			}
+			cell.Style = cell.Style.Overlay(self.Backdrop)
+			buf.SetCell(cell, point)
+		}
+	}
+}
+
+func (self *Layers) Draw(buf *Buffer) {
+	self.Block.Draw(buf)
+	modal := self.Modal()
+	for layer, items := range self.items {
+		for _, item := range items {
+			item.Lock()
+			if Layer(layer) == LayerModal {
+				if sameDrawable(item, modal) {
+					self.dim(buf)
+				}
+				self.center(item)
+			}
+			item.Draw(buf.Sub(item.GetRect()))
+			item.Unlock()
+		}
+	}
+}
+
+// HandleEvent sends the keyboard, paste and mouse events to the topmost modal,
+// closing it on <Escape>. It reports whether the event was used, and always does for
+// mouse events, which shouldn't reach the items below the modal.
+func (self *Layers) HandleEvent(e Event) bool {
+	modal := self.Modal()
+	if modal == nil {
+		return false
+	}
+	switch e.Type {
+	case KeyboardEvent:
+		if e.ID == "<Escape>" {
+			self.CloseModal()
+			return true
+		}
+	case MouseEvent:
+		m, _ := e.Payload.(Mouse)
+		if !image.Pt(m.X, m.Y).In(modal.GetRect()) {
+			return true
+		}
+	case PasteEvent:
+	default:
+		return false
+	}
+	handler, ok := modal.(interface{ HandleEvent(Event) bool })
+	return (ok && handler.HandleEvent(e)) || e.Type == MouseEvent
+}
+
+// HitTest returns the topmost item containing the point, as laid out by the last Draw.
+// While a modal is open, the items below it can't be hit.
+func (self *Layers) HitTest(point image.Point) Drawable {
+	if modal := self.Modal(); modal != nil {
+		if point.In(modal.GetRect()) {
+			return modal
+		}
+		return nil
+	}
+	for layer := len(self.items) - 1; layer >= 0; layer-- {
+		items := self.items[layer]
+		for i := len(items) - 1; i >= 0; i-- {
+			if point.In(items[i].GetRect()) {
+				return items[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Focusables returns the Focusable items of the topmost modal if there is one,
+// and of the base and overlay items otherwise.
+func (self *Layers) Focusables() []Focusable {
+	if modal := self.Modal(); modal != nil {
+		return focusables([]Drawable{modal})
+	}
+	return focusables(append(append([]Drawable{}, self.items[LayerBase]...), self.items[LayerOverlay]...))
+}
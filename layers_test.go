@@ -0,0 +1,237 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// runeBlock fills its rect with a rune and records the events it receives.
+type runeBlock struct {
+	Block
+	r      rune
+	events []string
+}
+
+func newRuneBlock(r rune, x1, y1, x2, y2 int) *runeBlock {
+	block := &runeBlock{Block: *NewBlock(), r: r}
+	block.SetRect(x1, y1, x2, y2)
+	return block
+}
+
+func (self *runeBlock) Draw(buf *Buffer) {
+	buf.Fill(NewCell(self.r), self.Rectangle)
+}
+
+func (self *runeBlock) HandleEvent(e Event) bool {
+	self.events = append(self.events, e.ID)
+	return e.ID == "x"
+}
+
+func drawLayers(layers *Layers) *Buffer {
+	buf := NewBuffer(layers.Rectangle)
+	layers.Draw(buf)
+	return buf
+}
+
+func bufferLines(buf *Buffer) string {
+	lines := []string{}
+	for y := buf.Min.Y; y < buf.Max.Y; y++ {
+		var sb strings.Builder
+		for x := buf.Min.X; x < buf.Max.X; x++ {
+			sb.WriteRune(buf.GetCell(image.Pt(x, y)).Rune)
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newTestLayers() *Layers {
+	layers := NewLayers()
+	layers.SetRect(0, 0, 6, 4)
+	return layers
+}
+
+func TestLayersZOrder(t *testing.T) {
+	layers := newTestLayers()
+	overlay := newRuneBlock('o', 1, 1, 4, 3)
+	modal := newRuneBlock('m', 3, 0, 5, 2)
+	layers.Push(LayerModal, modal)
+	layers.Push(LayerOverlay, overlay)
+	base := newRuneBlock('b', 0, 0, 0, 0)
+	layers.Push(LayerBase, base)
+	layers.Backdrop = StyleClear
+
+	if base.GetRect() != layers.Rectangle {
+		t.Errorf("expected base items to fill the layers, got %v", base.GetRect())
+	}
+	expected := strings.Join([]string{
+		"bbbmmb",
+		"boommb",
+		"booobb",
+		"bbbbbb",
+	}, "\n")
+	if s := bufferLines(drawLayers(layers)); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+
+	// pushing an item again moves it to the top of its layer
+	other := newRuneBlock('p', 0, 1, 3, 2)
+	layers.Push(LayerOverlay, other)
+	layers.Push(LayerOverlay, overlay)
+	if s := bufferLines(drawLayers(layers)); !strings.HasPrefix(s, "bbbmmb\npoommb") {
+		t.Errorf("expected the overlay above the other one, got:\n%s", s)
+	}
+
+	layers.SetRect(0, 0, 3, 2)
+	if base.GetRect() != image.Rect(0, 0, 3, 2) {
+		t.Errorf("expected base items to be resized with the layers, got %v", base.GetRect())
+	}
+}
+
+func TestLayersBackdrop(t *testing.T) {
+	layers := newTestLayers()
+	layers.Backdrop = NewStyle(ColorBlue, ColorClear, ModifierDim)
+	layers.Shade = '░'
+	layers.Push(LayerBase, newRuneBlock('b', 0, 0, 0, 0))
+	lower := newRuneBlock('l', 0, 0, 2, 2)
+	upper := newRuneBlock('u', 3, 1, 5, 3)
+	layers.Push(LayerModal, lower)
+	layers.Push(LayerModal, upper)
+
+	buf := drawLayers(layers)
+	expected := strings.Join([]string{
+		"░░░░░░",
+		"░░░uu░",
+		"░░░uu░",
+		"░░░░░░",
+	}, "\n")
+	if s := bufferLines(buf); s != expected {
+		t.Errorf("expected the cells below the topmost modal to be shaded:\n%s\ngot:\n%s", expected, s)
+	}
+	if style := buf.GetCell(image.Pt(0, 0)).Style; style != layers.Backdrop {
+		t.Errorf("expected the backdrop style below the modal, got %v", style)
+	}
+	if style := buf.GetCell(image.Pt(3, 1)).Style; style != StyleClear {
+		t.Errorf("expected the topmost modal not to be dimmed, got %v", style)
+	}
+
+	// without modals, nothing is dimmed
+	layers.CloseModal()
+	layers.CloseModal()
+	if s := bufferLines(drawLayers(layers)); strings.ContainsRune(s, '░') {
+		t.Errorf("expected no backdrop without modals, got:\n%s", s)
+	}
+}
+
+func TestLayersCenterModal(t *testing.T) {
+	layers := NewLayers()
+	layers.SetRect(0, 0, 10, 6)
+	sized := newSizedBlock(4, 2)
+	layers.Push(LayerModal, sized)
+	large := newSizedBlock(20, 3)
+	layers.Push(LayerModal, large)
+	empty := newSizedBlock(0, 0)
+	empty.SetRect(1, 1, 3, 3)
+	layers.Push(LayerModal, empty)
+	plain := newRuneBlock('p', 1, 2, 3, 4)
+	layers.Push(LayerModal, plain)
+	drawLayers(layers)
+
+	if rect := sized.GetRect(); rect != image.Rect(3, 2, 7, 4) {
+		t.Errorf("expected the modal to be centered, got %v", rect)
+	}
+	if rect := large.GetRect(); rect != image.Rect(0, 1, 10, 4) {
+		t.Errorf("expected the modal to be clamped to the layers, got %v", rect)
+	}
+	if rect := empty.GetRect(); rect != image.Rect(1, 1, 3, 3) {
+		t.Errorf("expected a modal without a preferred size to keep its rect, got %v", rect)
+	}
+	if rect := plain.GetRect(); rect != image.Rect(1, 2, 3, 4) {
+		t.Errorf("expected a modal which isn't a Sizer to keep its rect, got %v", rect)
+	}
+}
+
+func TestLayersEscapeClosesModal(t *testing.T) {
+	layers := newTestLayers()
+	lower := newRuneBlock('l', 0, 0, 2, 2)
+	upper := newRuneBlock('u', 3, 1, 5, 3)
+	layers.Push(LayerModal, lower)
+	layers.Push(LayerModal, upper)
+	closed := []Drawable{}
+	layers.OnClose = func(d Drawable) { closed = append(closed, d) }
+
+	escape := Event{Type: KeyboardEvent, ID: "<Escape>"}
+	if !layers.HandleEvent(escape) || layers.Modal() != lower {
+		t.Errorf("expected <Escape> to close the topmost modal")
+	}
+	if !layers.HandleEvent(escape) || layers.Modal() != nil {
+		t.Errorf("expected <Escape> to close the last modal")
+	}
+	if len(closed) != 2 || closed[0] != upper || closed[1] != lower {
+		t.Errorf("expected OnClose to be called with the closed modals, got %v", closed)
+	}
+	if layers.HandleEvent(escape) {
+		t.Error("expected <Escape> to be ignored without modals")
+	}
+	if len(upper.events) != 0 {
+		t.Errorf("expected <Escape> not to reach the modal, got %q", upper.events)
+	}
+}
+
+func TestLayersModalCapture(t *testing.T) {
+	layers := newTestLayers()
+	base := newRuneBlock('b', 0, 0, 0, 0)
+	layers.Push(LayerBase, base)
+	overlay := newRuneBlock('o', 0, 0, 2, 2)
+	layers.Push(LayerOverlay, overlay)
+
+	if hit := layers.HitTest(image.Pt(1, 1)); hit != overlay {
+		t.Errorf("expected the overlay to be hit, got %v", hit)
+	}
+	if hit := layers.HitTest(image.Pt(4, 3)); hit != base {
+		t.Errorf("expected the base item to be hit, got %v", hit)
+	}
+
+	modal := newRuneBlock('m', 3, 1, 5, 3)
+	layers.Push(LayerModal, modal)
+	if hit := layers.HitTest(image.Pt(1, 1)); hit != nil {
+		t.Errorf("expected nothing to be hit outside of the modal, got %v", hit)
+	}
+	if hit := layers.HitTest(image.Pt(3, 2)); hit != modal {
+		t.Errorf("expected the modal to be hit, got %v", hit)
+	}
+
+	outside := Event{Type: MouseEvent, ID: "<MouseLeft>", Payload: Mouse{X: 0, Y: 0, Button: MouseButtonLeft}}
+	inside := Event{Type: MouseEvent, ID: "<MouseLeft>", Payload: Mouse{X: 4, Y: 2, Button: MouseButtonLeft}}
+	testCases := []struct {
+		e        Event
+		used     bool
+		received bool
+	}{
+		{outside, true, false},
+		{inside, true, true},
+		{Event{Type: KeyboardEvent, ID: "x"}, true, true},
+		{Event{Type: KeyboardEvent, ID: "y"}, false, true},
+		{Event{Type: PasteEvent, ID: "<Paste>", Payload: Paste{Text: "z"}}, false, true},
+		{Event{Type: ResizeEvent, ID: "<Resize>"}, false, false},
+	}
+	for _, tc := range testCases {
+		modal.events = nil
+		if used := layers.HandleEvent(tc.e); used != tc.used {
+			t.Errorf("%s: expected used %v, got %v", tc.e.ID, tc.used, used)
+		}
+		if received := len(modal.events) == 1; received != tc.received {
+			t.Errorf("%s: expected received %v, got %q", tc.e.ID, tc.received, modal.events)
+		}
+	}
+	if len(base.events)+len(overlay.events) != 0 {
+		t.Errorf("expected the items below the modal not to receive events")
+	}
+}
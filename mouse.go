@@ -0,0 +1,167 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"reflect"
+	"time"
+)
+
+// MouseButton identifies the button of a MouseEvent.
+type MouseButton uint
+
+const (
+	MouseButtonNone MouseButton = iota
+	MouseButtonLeft
+	MouseButtonMiddle
+	MouseButtonRight
+	MouseButtonWheelUp
+	MouseButtonWheelDown
+)
+
+var mouseButtonIDs = map[MouseButton]string{
+	MouseButtonLeft:      "<MouseLeft>",
+	MouseButtonMiddle:    "<MouseMiddle>",
+	MouseButtonRight:     "<MouseRight>",
+	MouseButtonWheelUp:   "<MouseWheelUp>",
+	MouseButtonWheelDown: "<MouseWheelDown>",
+}
+
+// MouseAction is the state change reported by a MouseEvent.
+type MouseAction uint
+
+const (
+	MouseActionPress MouseAction = iota
+	MouseActionRelease
+	// MouseActionDrag reports a move of the mouse while a button is held.
+	MouseActionDrag
+	// MouseActionMove reports a move of the mouse without any button held,
+	// which is only sent by some terminals.
+	MouseActionMove
+)
+
+// doubleClickInterval is the maximum delay between the presses of a double or triple click.
+const doubleClickInterval = 500 * time.Millisecond
+
+// mouseEventID returns the event ID of a Mouse payload.
+func mouseEventID(m Mouse) string {
+	switch m.Action {
+	case MouseActionRelease:
+		return "<MouseRelease>"
+	case MouseActionMove:
+		return "<MouseMove>"
+	}
+	if id, ok := mouseButtonIDs[m.Button]; ok {
+		return id
+	}
+	return "Unknown_Mouse_Button"
+}
+
+// newMouseEvent returns the MouseEvent of a Mouse payload.
+func newMouseEvent(m Mouse) Event {
+	m.Drag = m.Action == MouseActionDrag
+	return Event{
+		Type:    MouseEvent,
+		ID:      mouseEventID(m),
+		Payload: m,
+	}
+}
+
+// mouseTracker pairs presses with releases and counts consecutive clicks.
+type mouseTracker struct {
+	pressed   MouseButton
+	lastPress Mouse
+	lastTime  time.Time
+}
+
+// track fills the Button of releases and drags which don't report it and the Clicks of presses and releases.
+func (self *mouseTracker) track(m Mouse, now time.Time) Mouse {
+	switch m.Action {
+	case MouseActionPress:
+		if m.Button == MouseButtonWheelUp || m.Button == MouseButtonWheelDown {
+			m.Clicks = 1
+			return m
+		}
+		m.Clicks = 1
+		last := self.lastPress
+		if last.Button == m.Button && last.X == m.X && last.Y == m.Y &&
+			now.Sub(self.lastTime) <= doubleClickInterval && last.Clicks < 3 {
+			m.Clicks = last.Clicks + 1
+		}
+		self.pressed = m.Button
+		self.lastPress = m
+		self.lastTime = now
+	case MouseActionDrag:
+		if m.Button == MouseButtonNone {
+			m.Button = self.pressed
+		}
+	case MouseActionRelease:
+		if m.Button == MouseButtonNone {
+			m.Button = self.pressed
+		}
+		if m.Button == self.lastPress.Button {
+			m.Clicks = self.lastPress.Clicks
+		}
+		self.pressed = MouseButtonNone
+	}
+	return m
+}
+
+// trackEvent applies track to the payload of a MouseEvent.
+func (self *mouseTracker) trackEvent(e Event) Event {
+	if e.Type != MouseEvent {
+		return e
+	}
+	if m, ok := e.Payload.(Mouse); ok {
+		return newMouseEvent(self.track(m, time.Now()))
+	}
+	return e
+}
+
+// HitTester is implemented by containers like Grid, to find the item at a point.
+type HitTester interface {
+	// HitTest returns the topmost item containing the point, or nil.
+	HitTest(image.Point) Drawable
+}
+
+// sameDrawable compares Drawables without panicking on uncomparable types.
+func sameDrawable(a, b Drawable) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// HitTest returns the Drawable at x, y among the items drawn by the last Render,
+// with the items rendered last on top.
+// The items of containers implementing HitTester, like Grid, are searched as well.
+// It returns nil if there is no item at x, y.
+func HitTest(x, y int) Drawable {
+	point := image.Pt(x, y)
+	renderer.Lock()
+	items := renderer.items
+	renderer.Unlock()
+
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if !point.In(item.GetRect()) {
+			continue
+		}
+		for {
+			container, ok := item.(HitTester)
+			if !ok {
+				break
+			}
+			child := container.HitTest(point)
+			if child == nil || sameDrawable(child, item) {
+				break
+			}
+			item = child
+		}
+		return item
+	}
+	return nil
+}
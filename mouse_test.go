@@ -0,0 +1,82 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+func TestHitTest(t *testing.T) {
+	SetBackend(NewSimulationBackend(20, 10))
+	defer SetBackend(NewTermboxBackend())
+
+	left, right, over := NewBlock(), NewBlock(), NewBlock()
+	left.SetRect(0, 0, 10, 10)
+	right.SetRect(10, 0, 20, 10)
+	over.SetRect(5, 2, 15, 8)
+	Render(left, right, over)
+
+	testCases := []struct {
+		x, y     int
+		expected Drawable
+	}{
+		{1, 1, left},
+		{19, 9, right},
+		{5, 2, over},
+		{14, 7, over},
+		{20, 5, nil},
+	}
+	for _, tc := range testCases {
+		if item := HitTest(tc.x, tc.y); item != tc.expected {
+			t.Errorf("%d, %d: expected %p, got %p", tc.x, tc.y, tc.expected, item)
+		}
+	}
+
+	// rendering an item again moves it on top
+	Render(left, right, over, left)
+	if item := HitTest(5, 2); item != left {
+		t.Errorf("expected %p, got %p", left, item)
+	}
+
+	// only the items of the last Render are hit
+	Render(right)
+	if item := HitTest(1, 1); item != nil {
+		t.Errorf("expected no item, got %p", item)
+	}
+	if item := HitTest(15, 1); item != right {
+		t.Errorf("expected %p, got %p", right, item)
+	}
+}
+
+func TestHitTestConcurrentRender(t *testing.T) {
+	SetBackend(NewSimulationBackend(20, 10))
+	defer SetBackend(NewTermboxBackend())
+
+	blocks := make([]Drawable, 5)
+	for i := range blocks {
+		block := NewBlock()
+		block.SetRect(i*4, 0, i*4+4, 10)
+		blocks[i] = block
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2000; i++ {
+			Render(blocks[i%len(blocks):]...)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 2000; i++ {
+			HitTest(i%20, 5)
+		}
+	}()
+	wg.Wait()
+}
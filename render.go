@@ -7,8 +7,6 @@ package termui
 import (
 	"image"
 	"sync"
-
-	tb "github.com/nsf/termbox-go"
 )
 
 type Drawable interface {
@@ -18,21 +16,146 @@ type Drawable interface {
 	sync.Locker
 }
 
+// RenderStats holds counters about the work done by Render.
+type RenderStats struct {
+	// Frames is the number of calls to Render.
+	Frames int
+	// FramesSkipped is the number of frames in which no cell changed,
+	// so the backend wasn't flushed.
+	FramesSkipped int
+	// CellsWritten is the number of cells sent to the backend.
+	CellsWritten int
+	// CellsSkipped is the number of cells left untouched because they didn't change
+	// since the previous frame.
+	CellsSkipped int
+}
+
+// invalidCell never matches a drawn cell and marks front buffer cells whose content is unknown.
+var invalidCell = Cell{Rune: -1}
+
+// renderer keeps a front buffer of what has been sent to the backend,
+// so that Render only writes the cells which changed since the previous frame.
+// It also keeps the items of the last Render, in drawing order, for HitTest.
+// The items slice is replaced rather than modified, so HitTest can read it without the lock.
+var renderer = struct {
+	front *Buffer
+	// cleared is set when the backend has been cleared but not flushed yet
+	cleared bool
+	stats   RenderStats
+	items   []Drawable
+	sync.Mutex
+}{}
+
+// syncFrontBuffer resets the front buffer if the size of the backend changed.
+// Must be called with the renderer locked.
+func syncFrontBuffer() {
+	width, height := backend.Size()
+	rect := image.Rect(0, 0, width, height)
+	if renderer.front == nil || renderer.front.Rectangle != rect {
+		renderer.front = NewBuffer(rect)
+		renderer.front.Fill(invalidCell, rect)
+	}
+}
+
+// resetFrontBuffer records that the backend has been cleared with the given style.
+func resetFrontBuffer(style Style) {
+	renderer.Lock()
+	defer renderer.Unlock()
+	syncFrontBuffer()
+	renderer.front.Fill(Cell{' ', style}, renderer.front.Rectangle)
+	renderer.cleared = true
+	renderer.items = nil
+}
+
+// addRenderedItem moves the item to the top of the rendered items.
+func addRenderedItem(items []Drawable, item Drawable) []Drawable {
+	rendered := items[:0]
+	for _, other := range items {
+		if !sameDrawable(other, item) {
+			rendered = append(rendered, other)
+		}
+	}
+	return append(rendered, item)
+}
+
+// Invalidate forgets the content of the front buffer so that the next Render redraws every cell.
+// It is useful if something other than Render wrote to the terminal.
+func Invalidate() {
+	renderer.Lock()
+	defer renderer.Unlock()
+	renderer.front = nil
+}
+
+// GetRenderStats returns the counters accumulated by Render.
+func GetRenderStats() RenderStats {
+	renderer.Lock()
+	defer renderer.Unlock()
+	return renderer.stats
+}
+
+// ResetRenderStats sets all the counters returned by GetRenderStats back to zero.
+func ResetRenderStats() {
+	renderer.Lock()
+	defer renderer.Unlock()
+	renderer.stats = RenderStats{}
+}
+
+// Render draws the items to the terminal, writing only the cells which changed since
+// the previous frame. The items are drawn in order into a single frame, so the cells
+// where they overlap are only written once. The backend isn't flushed at all if nothing
+// changed since the previous frame or the last Clear.
+// The items replace the ones of the previous Render for HitTest.
 func Render(items ...Drawable) {
+	renderer.Lock()
+	defer renderer.Unlock()
+
+	syncFrontBuffer()
+	front := renderer.front
+	stats := &renderer.stats
+	stats.Frames++
+
+	// compose the frame on top of what is on the screen, recording the cells drawn
+	back := NewBuffer(front.Rectangle)
+	copy(back.cells, front.cells)
+	drawn := make([]bool, len(back.cells))
+	rendered := make([]Drawable, 0, len(items))
 	for _, item := range items {
 		buf := NewBuffer(item.GetRect())
 		item.Lock()
 		item.Draw(buf)
 		item.Unlock()
-		for point, cell := range buf.CellMap {
-			if point.In(buf.Rectangle) {
-				tb.SetCell(
-					point.X, point.Y,
-					cell.Rune,
-					tb.Attribute(cell.Style.Fg+1)|tb.Attribute(cell.Style.Modifier), tb.Attribute(cell.Style.Bg+1),
-				)
+		rendered = addRenderedItem(rendered, item)
+		visible := buf.Intersect(back.Rectangle)
+		for y := visible.Min.Y; y < visible.Max.Y; y++ {
+			for x := visible.Min.X; x < visible.Max.X; x++ {
+				point := image.Pt(x, y)
+				i := back.index(point)
+				back.cells[i] = buf.cells[buf.index(point)]
+				drawn[i] = true
 			}
 		}
 	}
-	tb.Flush()
+
+	written := 0
+	for i, cell := range back.cells {
+		if !drawn[i] {
+			continue
+		}
+		if front.cells[i] == cell {
+			stats.CellsSkipped++
+			continue
+		}
+		backend.SetCell(back.Min.X+i%back.Dx(), back.Min.Y+i/back.Dx(), cell)
+		front.cells[i] = cell
+		written++
+	}
+
+	renderer.items = rendered
+	stats.CellsWritten += written
+	if written == 0 && !renderer.cleared {
+		stats.FramesSkipped++
+		return
+	}
+	renderer.cleared = false
+	backend.Flush()
 }
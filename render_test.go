@@ -0,0 +1,107 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// useSimulationBackend replaces the backend and the render stats for the duration of a test.
+func useSimulationBackend(t *testing.T, width, height int) *SimulationBackend {
+	sim := NewSimulationBackend(width, height)
+	SetBackend(sim)
+	ResetRenderStats()
+	t.Cleanup(func() {
+		SetBackend(NewTermboxBackend())
+		ResetRenderStats()
+	})
+	return sim
+}
+
+func expectStats(t *testing.T, expected RenderStats) {
+	t.Helper()
+	if stats := GetRenderStats(); stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestRenderSkipsUnchangedCells(t *testing.T) {
+	useSimulationBackend(t, 10, 5)
+	block := NewBlock()
+	block.Title = "a"
+	block.SetRect(0, 0, 5, 3)
+
+	Render(block)
+	expectStats(t, RenderStats{Frames: 1, CellsWritten: 15})
+
+	Render(block)
+	expectStats(t, RenderStats{Frames: 2, FramesSkipped: 1, CellsWritten: 15, CellsSkipped: 15})
+
+	block.Title = "b"
+	Render(block)
+	expectStats(t, RenderStats{Frames: 3, FramesSkipped: 1, CellsWritten: 16, CellsSkipped: 29})
+}
+
+func TestRenderOverlappingItems(t *testing.T) {
+	sim := useSimulationBackend(t, 10, 5)
+	grid := NewBlock()
+	grid.SetRect(0, 0, 6, 4)
+	popup := NewBlock()
+	popup.SetRect(2, 1, 8, 4)
+
+	Render(grid, popup)
+	// the overlap is drawn once, with the cells of the popup
+	expectStats(t, RenderStats{Frames: 1, CellsWritten: 6*4 + 2*3})
+	expected := strings.Join([]string{
+		"┌────┐    ",
+		"│ ┌────┐  ",
+		"│ │    │  ",
+		"└─└────┘  ",
+		"          ",
+	}, "\n")
+	if s := sim.String(); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+
+	Render(grid, popup)
+	expectStats(t, RenderStats{Frames: 2, FramesSkipped: 1, CellsWritten: 30, CellsSkipped: 30})
+}
+
+func TestRenderInvalidate(t *testing.T) {
+	useSimulationBackend(t, 10, 5)
+	block := NewBlock()
+	block.SetRect(0, 0, 5, 3)
+
+	Render(block)
+	Invalidate()
+	Render(block)
+	expectStats(t, RenderStats{Frames: 2, CellsWritten: 30})
+}
+
+func TestRenderAfterClear(t *testing.T) {
+	sim := useSimulationBackend(t, 4, 2)
+	block := NewBlock()
+	block.SetRect(0, 0, 4, 2)
+	Render(block)
+
+	Clear()
+	Render()
+	if s := sim.String(); s != "    \n    " {
+		t.Errorf("expected Render to flush the cleared screen, got:\n%s", s)
+	}
+	expectStats(t, RenderStats{Frames: 2, CellsWritten: 8})
+
+	// the cleared cells are known, so only the block is written again
+	Render(block)
+	expectStats(t, RenderStats{Frames: 3, CellsWritten: 16})
+	Render(block)
+	expectStats(t, RenderStats{Frames: 4, FramesSkipped: 1, CellsWritten: 16, CellsSkipped: 8})
+	if s := sim.String(); s != "┌──┐\n└──┘" {
+		t.Errorf("expected the block to be drawn again, got:\n%s", s)
+	}
+}
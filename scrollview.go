@@ -0,0 +1,213 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// ScrollView shows a part of a Drawable larger than its Inner rectangle, with scrollbars
+// along the axes on which the content doesn't fit.
+//
+// The content is laid out at its full size, moved by the offset, and clipped to the viewport,
+// so any Drawable can be scrolled without knowing about it.
+type ScrollView struct {
+	Block
+	Content Drawable
+	// ContentSize is the size of the content. If it is zero, the preferred size of the content
+	// is used if it implements Sizer, and the size of the viewport otherwise.
+	ContentSize image.Point
+	// Offset is the position of the content shown in the top left corner of the viewport.
+	Offset         image.Point
+	ScrollbarStyle Style
+	ThumbStyle     Style
+}
+
+func NewScrollView(content Drawable) *ScrollView {
+	return &ScrollView{
+		Block:          *NewBlock(),
+		Content:        content,
+		ScrollbarStyle: Theme.ScrollView.Scrollbar,
+		ThumbStyle:     Theme.ScrollView.Thumb,
+	}
+}
+
+// contentSize returns the size of the content laid out in width columns.
+func (self *ScrollView) contentSize(width int) image.Point {
+	if self.ContentSize != (image.Point{}) {
+		return self.ContentSize
+	}
+	if sizer, ok := self.Content.(Sizer); ok {
+		return sizer.PreferredSize(width)
+	}
+	return image.Point{}
+}
+
+// layout returns the size of the content and the viewport, which is Inner without the scrollbars.
+func (self *ScrollView) layout() (image.Point, image.Rectangle) {
+	viewport := self.Inner
+	size := self.contentSize(viewport.Dx())
+	vertical := size.Y > viewport.Dy()
+	if vertical {
+		viewport.Max.X--
+		size = self.contentSize(viewport.Dx())
+	}
+	if size.X > viewport.Dx() {
+		viewport.Max.Y--
+		if !vertical && size.Y > viewport.Dy() {
+			viewport.Max.X--
+		}
+	}
+	size.X, size.Y = MaxInt(size.X, viewport.Dx()), MaxInt(size.Y, viewport.Dy())
+	return size, viewport
+}
+
+// clampOffset keeps the viewport inside the content.
+func (self *ScrollView) clampOffset(size image.Point, viewport image.Rectangle) {
+	self.Offset.X = MaxInt(MinInt(self.Offset.X, size.X-viewport.Dx()), 0)
+	self.Offset.Y = MaxInt(MinInt(self.Offset.Y, size.Y-viewport.Dy()), 0)
+}
+
+// ScrollBy moves the viewport by dx columns and dy rows.
+func (self *ScrollView) ScrollBy(dx, dy int) {
+	self.ScrollTo(self.Offset.X+dx, self.Offset.Y+dy)
+}
+
+// ScrollTo shows the content from column x and row y.
+func (self *ScrollView) ScrollTo(x, y int) {
+	self.Offset = image.Pt(x, y)
+	self.clampOffset(self.layout())
+}
+
+func (self *ScrollView) ScrollPageUp() {
+	_, viewport := self.layout()
+	self.ScrollBy(0, -viewport.Dy())
+}
+
+func (self *ScrollView) ScrollPageDown() {
+	_, viewport := self.layout()
+	self.ScrollBy(0, viewport.Dy())
+}
+
+func (self *ScrollView) ScrollTop() {
+	self.ScrollTo(self.Offset.X, 0)
+}
+
+func (self *ScrollView) ScrollBottom() {
+	size, _ := self.layout()
+	self.ScrollTo(self.Offset.X, size.Y)
+}
+
+// thumb returns the position and length of the thumb of a scrollbar of length cells.
+func thumb(length, offset, view, content int) (int, int) {
+	if length <= 0 {
+		return 0, 0
+	}
+	if content <= view {
+		return 0, length
+	}
+	size := MaxInt(length*view/content, 1)
+	return (length - size) * offset / (content - view), size
+}
+
+func (self *ScrollView) drawScrollbars(buf *Buffer, size image.Point, viewport image.Rectangle) {
+	track := Cell{SHADED_BLOCKS[1], self.ScrollbarStyle}
+	thumbCell := Cell{SHADED_BLOCKS[4], self.ThumbStyle}
+	if viewport.Max.X < self.Inner.Max.X {
+		x := viewport.Max.X
+		buf.Fill(track, image.Rect(x, viewport.Min.Y, x+1, viewport.Max.Y))
+		position, length := thumb(viewport.Dy(), self.Offset.Y, viewport.Dy(), size.Y)
+		y := viewport.Min.Y + position
+		buf.Fill(thumbCell, image.Rect(x, y, x+1, y+length))
+	}
+	if viewport.Max.Y < self.Inner.Max.Y {
+		y := viewport.Max.Y
+		buf.Fill(track, image.Rect(viewport.Min.X, y, viewport.Max.X, y+1))
+		position, length := thumb(viewport.Dx(), self.Offset.X, viewport.Dx(), size.X)
+		x := viewport.Min.X + position
+		buf.Fill(thumbCell, image.Rect(x, y, x+length, y+1))
+	}
+}
+
+func (self *ScrollView) Draw(buf *Buffer) {
+	self.Block.Draw(buf)
+	if self.Content == nil {
+		return
+	}
+	size, viewport := self.layout()
+	self.clampOffset(size, viewport)
+
+	min := viewport.Min.Sub(self.Offset)
+	self.Content.Lock()
+	self.Content.SetRect(min.X, min.Y, min.X+size.X, min.Y+size.Y)
+	self.Content.Draw(buf.Sub(viewport))
+	self.Content.Unlock()
+
+	self.drawScrollbars(buf, size, viewport)
+}
+
+// scrollToPoint scrolls to the position of a click on a scrollbar.
+func (self *ScrollView) scrollToPoint(point image.Point) bool {
+	size, viewport := self.layout()
+	switch {
+	case point.X == viewport.Max.X && viewport.Max.X < self.Inner.Max.X &&
+		point.Y >= viewport.Min.Y && point.Y < viewport.Max.Y:
+		y := (point.Y - viewport.Min.Y) * (size.Y - viewport.Dy()) / MaxInt(viewport.Dy()-1, 1)
+		self.ScrollTo(self.Offset.X, y)
+	case point.Y == viewport.Max.Y && viewport.Max.Y < self.Inner.Max.Y &&
+		point.X >= viewport.Min.X && point.X < viewport.Max.X:
+		x := (point.X - viewport.Min.X) * (size.X - viewport.Dx()) / MaxInt(viewport.Dx()-1, 1)
+		self.ScrollTo(x, self.Offset.Y)
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleEvent implements the Focusable interface: arrows and h/j/k/l scroll by a cell,
+// page keys by a page, <Home>/g and <End>/G to the top and bottom, the mouse wheel
+// scrolls vertically, and clicking or dragging on a scrollbar moves the thumb there.
+func (self *ScrollView) HandleEvent(e Event) bool {
+	switch e.ID {
+	case "<Up>", "k", "<MouseWheelUp>":
+		self.ScrollBy(0, -1)
+	case "<Down>", "j", "<MouseWheelDown>":
+		self.ScrollBy(0, 1)
+	case "<Left>", "h":
+		self.ScrollBy(-1, 0)
+	case "<Right>", "l":
+		self.ScrollBy(1, 0)
+	case "<PageUp>":
+		self.ScrollPageUp()
+	case "<PageDown>":
+		self.ScrollPageDown()
+	case "<Home>", "g":
+		self.ScrollTop()
+	case "<End>", "G":
+		self.ScrollBottom()
+	case "<MouseLeft>":
+		m, _ := e.Payload.(Mouse)
+		if m.Action != MouseActionPress && m.Action != MouseActionDrag {
+			return false
+		}
+		return self.scrollToPoint(image.Pt(m.X, m.Y))
+	default:
+		return false
+	}
+	return true
+}
+
+// PreferredSize implements the Sizer interface: the content without scrolling.
+func (self *ScrollView) PreferredSize(width int) image.Point {
+	return self.OuterSize(self.contentSize(self.InnerWidth(width)))
+}
+
+// HitTest returns the content if the point is inside the viewport.
+func (self *ScrollView) HitTest(point image.Point) Drawable {
+	if _, viewport := self.layout(); self.Content != nil && point.In(viewport) {
+		return self.Content
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestThumb(t *testing.T) {
+	testCases := []struct {
+		length, offset, view, content int
+		expectedPosition              int
+		expectedSize                  int
+	}{
+		{10, 0, 10, 5, 0, 10},
+		{10, 0, 10, 20, 0, 5},
+		{10, 10, 10, 20, 5, 5},
+		{10, 5, 10, 20, 2, 5},
+		{10, 90, 10, 100, 9, 1},
+		{0, 5, 0, 20, 0, 0},
+		{-1, 5, 0, 20, 0, 0},
+	}
+	for _, tc := range testCases {
+		position, size := thumb(tc.length, tc.offset, tc.view, tc.content)
+		if position != tc.expectedPosition || size != tc.expectedSize {
+			t.Errorf("thumb(%d, %d, %d, %d): expected %d, %d, got %d, %d",
+				tc.length, tc.offset, tc.view, tc.content,
+				tc.expectedPosition, tc.expectedSize, position, size)
+		}
+	}
+}
+
+func TestScrollViewContentSize(t *testing.T) {
+	// a Block has no preferred size, so it fills the viewport
+	sv := NewScrollView(NewBlock())
+	sv.SetRect(0, 0, 20, 10)
+	sv.Draw(NewBuffer(sv.Rectangle))
+	if rect := sv.Content.GetRect(); rect != sv.Inner {
+		t.Errorf("expected %v, got %v", sv.Inner, rect)
+	}
+
+	sv.ContentSize = image.Pt(30, 40)
+	sv.ScrollTo(100, 100)
+	size, viewport := sv.layout()
+	if size != sv.ContentSize || viewport != image.Rect(1, 1, 18, 8) {
+		t.Errorf("expected %v and %v, got %v and %v", sv.ContentSize, image.Rect(1, 1, 18, 8), size, viewport)
+	}
+	if expected := image.Pt(30-17, 40-7); sv.Offset != expected {
+		t.Errorf("expected offset %v, got %v", expected, sv.Offset)
+	}
+}
+
+// coordinateContent draws the column of each cell as a letter and its row as the foreground color.
+type coordinateContent struct {
+	Block
+}
+
+func (self *coordinateContent) Draw(buf *Buffer) {
+	for y := self.Min.Y; y < self.Max.Y; y++ {
+		for x := self.Min.X; x < self.Max.X; x++ {
+			buf.SetCell(NewCell(rune('a'+(x-self.Min.X)%26), NewStyle(Color(y-self.Min.Y))), image.Pt(x, y))
+		}
+	}
+}
+
+// newTestScrollView returns a ScrollView with a 17x7 viewport on a 30x40 content.
+func newTestScrollView() *ScrollView {
+	sv := NewScrollView(&coordinateContent{*NewBlock()})
+	sv.ContentSize = image.Pt(30, 40)
+	sv.SetRect(0, 0, 20, 10)
+	return sv
+}
+
+func scrollMouseEvent(x, y int, action MouseAction) Event {
+	return Event{Type: MouseEvent, ID: "<MouseLeft>", Payload: Mouse{X: x, Y: y, Button: MouseButtonLeft, Action: action}}
+}
+
+func TestScrollViewHandleEvent(t *testing.T) {
+	sv := newTestScrollView()
+	for _, step := range []struct {
+		id       string
+		expected image.Point
+	}{
+		{"j", image.Pt(0, 1)},
+		{"<Down>", image.Pt(0, 2)},
+		{"k", image.Pt(0, 1)},
+		{"<Up>", image.Pt(0, 0)},
+		{"<Up>", image.Pt(0, 0)},
+		{"h", image.Pt(0, 0)},
+		{"l", image.Pt(1, 0)},
+		{"<Right>", image.Pt(2, 0)},
+		{"<Left>", image.Pt(1, 0)},
+		{"<PageDown>", image.Pt(1, 7)},
+		{"<PageUp>", image.Pt(1, 0)},
+		{"G", image.Pt(1, 33)},
+		{"<Down>", image.Pt(1, 33)},
+		{"<PageDown>", image.Pt(1, 33)},
+		{"g", image.Pt(1, 0)},
+		{"<End>", image.Pt(1, 33)},
+		{"<Home>", image.Pt(1, 0)},
+		{"<MouseWheelDown>", image.Pt(1, 1)},
+		{"<MouseWheelUp>", image.Pt(1, 0)},
+	} {
+		if !sv.HandleEvent(Event{Type: KeyboardEvent, ID: step.id}) {
+			t.Errorf("%s: expected the event to be used", step.id)
+		}
+		if sv.Offset != step.expected {
+			t.Fatalf("%s: expected offset %v, got %v", step.id, step.expected, sv.Offset)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		sv.HandleEvent(Event{Type: KeyboardEvent, ID: "l"})
+	}
+	if sv.Offset != image.Pt(13, 0) {
+		t.Errorf("expected the offset to be clamped to %v, got %v", image.Pt(13, 0), sv.Offset)
+	}
+	if sv.HandleEvent(Event{Type: KeyboardEvent, ID: "x"}) {
+		t.Error("expected x to be unused")
+	}
+}
+
+func TestScrollViewScrollbarMouse(t *testing.T) {
+	sv := newTestScrollView()
+	for _, step := range []struct {
+		name     string
+		event    Event
+		used     bool
+		expected image.Point
+	}{
+		{"vertical bottom", scrollMouseEvent(18, 7, MouseActionPress), true, image.Pt(0, 33)},
+		{"vertical top", scrollMouseEvent(18, 1, MouseActionPress), true, image.Pt(0, 0)},
+		{"vertical drag", scrollMouseEvent(18, 4, MouseActionDrag), true, image.Pt(0, 16)},
+		{"release", scrollMouseEvent(18, 7, MouseActionRelease), false, image.Pt(0, 16)},
+		{"horizontal end", scrollMouseEvent(17, 8, MouseActionPress), true, image.Pt(13, 16)},
+		{"horizontal drag", scrollMouseEvent(9, 8, MouseActionDrag), true, image.Pt(6, 16)},
+		{"viewport", scrollMouseEvent(5, 5, MouseActionPress), false, image.Pt(6, 16)},
+		{"corner", scrollMouseEvent(18, 8, MouseActionPress), false, image.Pt(6, 16)},
+		{"border", scrollMouseEvent(19, 4, MouseActionPress), false, image.Pt(6, 16)},
+	} {
+		if used := sv.HandleEvent(step.event); used != step.used {
+			t.Errorf("%s: expected used to be %v", step.name, step.used)
+		}
+		if sv.Offset != step.expected {
+			t.Fatalf("%s: expected offset %v, got %v", step.name, step.expected, sv.Offset)
+		}
+	}
+}
+
+func TestScrollViewDrawClipsContent(t *testing.T) {
+	sv := newTestScrollView()
+	sv.ScrollTo(2, 3)
+	buf := NewBuffer(sv.Rectangle)
+	sv.Draw(buf)
+
+	for _, tc := range []struct {
+		point    image.Point
+		expected rune
+		row      Color
+	}{
+		{image.Pt(1, 1), 'c', 3},
+		{image.Pt(17, 1), 's', 3},
+		{image.Pt(1, 7), 'c', 9},
+		{image.Pt(17, 7), 's', 9},
+	} {
+		cell := buf.GetCell(tc.point)
+		if cell.Rune != tc.expected || cell.Style.Fg != tc.row {
+			t.Errorf("%v: expected %c on row %d, got %c on row %d", tc.point, tc.expected, tc.row, cell.Rune, cell.Style.Fg)
+		}
+	}
+
+	// the content isn't drawn over the border and the scrollbars
+	for _, tc := range []struct {
+		point    image.Point
+		expected rune
+	}{
+		{image.Pt(0, 0), '┌'},
+		{image.Pt(1, 0), '─'},
+		{image.Pt(0, 1), '│'},
+		{image.Pt(19, 1), '│'},
+		{image.Pt(1, 9), '─'},
+		{image.Pt(18, 1), SHADED_BLOCKS[4]},
+		{image.Pt(18, 2), SHADED_BLOCKS[1]},
+		{image.Pt(1, 8), SHADED_BLOCKS[1]},
+		{image.Pt(2, 8), SHADED_BLOCKS[4]},
+		{image.Pt(10, 8), SHADED_BLOCKS[4]},
+		{image.Pt(11, 8), SHADED_BLOCKS[1]},
+		{image.Pt(17, 8), SHADED_BLOCKS[1]},
+		{image.Pt(18, 8), ' '},
+	} {
+		if r := buf.GetCell(tc.point).Rune; r != tc.expected {
+			t.Errorf("%v: expected %c, got %c", tc.point, tc.expected, r)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+
+	rw "github.com/mattn/go-runewidth"
+)
+
+// Sizer is implemented by the widgets which can report how much space they want.
+// Both methods are given the width available to the widget, which text widgets use to wrap
+// their content, and return an outer size including the borders and padding.
+// Block only implements MinSize, so that the widgets embedding it implement Sizer once
+// they can measure their content with PreferredSize, and the other ones don't.
+type Sizer interface {
+	// PreferredSize returns the size needed to show the whole content of the widget.
+	PreferredSize(width int) image.Point
+	// MinSize returns the smallest size at which the widget is still usable.
+	MinSize(width int) image.Point
+}
+
+// CellsSize returns the width of the widest line of cells and the number of lines,
+// the lines being separated by '\n' cells.
+func CellsSize(cells []Cell) image.Point {
+	size := image.Point{}
+	for _, line := range SplitCells(cells, '\n') {
+		width := 0
+		for _, cell := range line {
+			width += rw.RuneWidth(cell.Rune)
+		}
+		size.X = MaxInt(size.X, width)
+		size.Y++
+	}
+	return size
+}
+
+// chrome returns the number of cells taken by the margins, shadow, border and padding around Inner.
+func (self *Block) chrome() image.Point {
+	insets := self.insets()
+	return insets.Min.Add(insets.Max)
+}
+
+// OuterSize returns the size of the block given the size of its Inner rectangle,
+// widened so that the titles fit.
+func (self *Block) OuterSize(inner image.Point) image.Point {
+	size := inner.Add(self.chrome())
+	size.X = MaxInt(size.X, self.titlesWidth()+self.outside().X)
+	return size
+}
+
+// InnerWidth returns the width of the Inner rectangle of the block given its outer width.
+func (self *Block) InnerWidth(width int) int {
+	return MaxInt(width-self.chrome().X, 0)
+}
+
+// MinSize implements the Sizer interface. A block needs one cell of content.
+func (self *Block) MinSize(width int) image.Point {
+	return self.chrome().Add(image.Pt(1, 1))
+}
@@ -0,0 +1,275 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// SplitDirection is the axis along which a SplitPane lays its panes out.
+type SplitDirection uint
+
+const (
+	// SplitHorizontal lays the panes out side by side, separated by vertical dividers.
+	SplitHorizontal SplitDirection = iota
+	// SplitVertical stacks the panes, separated by horizontal dividers.
+	SplitVertical
+)
+
+// Pane is a Drawable laid out by a SplitPane.
+type Pane struct {
+	Drawable
+	// Ratio is the share of the SplitPane given to the pane, relative to the ratios of the other panes.
+	Ratio float64
+	// Min is the minimum size of the pane in cells.
+	Min int
+}
+
+// SplitPane shares its area between panes separated by dividers, which can be dragged with
+// the mouse, or selected with [ and ] and moved with the arrow keys once the SplitPane is focused.
+//
+// A SplitPane lists itself before the Focusables of its panes, so that a FocusManager sends it
+// the mouse events on the dividers.
+type SplitPane struct {
+	Block
+	Direction SplitDirection
+	Panes     []*Pane
+	// DividerStyle is the style of the dividers, and SelectedDividerStyle the one of the divider
+	// being dragged, or of the selected divider while the SplitPane is focused.
+	DividerStyle         Style
+	SelectedDividerStyle Style
+	// OnResize is called with the ratios of the panes after a divider was moved.
+	OnResize func([]float64)
+
+	selectedDivider int
+	dragging        bool
+}
+
+// NewSplitPane returns a SplitPane sharing its area equally between the items.
+func NewSplitPane(direction SplitDirection, items ...Drawable) *SplitPane {
+	s := &SplitPane{
+		Block:                *NewBlock(),
+		Direction:            direction,
+		DividerStyle:         Theme.SplitPane.Divider,
+		SelectedDividerStyle: Theme.SplitPane.SelectedDivider,
+	}
+	s.Border = false
+	for _, item := range items {
+		s.Panes = append(s.Panes, &Pane{Drawable: item, Ratio: 1})
+	}
+	return s
+}
+
+// SetRatios sets the ratios of the panes, e.g. to restore the ones reported by OnResize.
+func (self *SplitPane) SetRatios(ratios ...float64) {
+	for i, ratio := range ratios {
+		if i < len(self.Panes) {
+			self.Panes[i].Ratio = ratio
+		}
+	}
+}
+
+// Ratios returns the ratios of the panes, normalized so that they add up to 1.
+func (self *SplitPane) Ratios() []float64 {
+	ratios := make([]float64, len(self.Panes))
+	total := 0.0
+	for _, pane := range self.Panes {
+		total += pane.Ratio
+	}
+	for i, pane := range self.Panes {
+		if total > 0 {
+			ratios[i] = pane.Ratio / total
+		}
+	}
+	return ratios
+}
+
+// area returns the rectangle in which the panes are laid out.
+func (self *SplitPane) area() image.Rectangle {
+	return self.Inner
+}
+
+// axis returns the start and length of a rectangle along the axis of the SplitPane.
+func (self *SplitPane) axis(rect image.Rectangle) (int, int) {
+	if self.Direction == SplitVertical {
+		return rect.Min.Y, rect.Dy()
+	}
+	return rect.Min.X, rect.Dx()
+}
+
+// sizes returns the sizes of the panes, the dividers taking one cell between them.
+func (self *SplitPane) sizes() []int {
+	items := make([]*GridItem, len(self.Panes))
+	for i, pane := range self.Panes {
+		items[i] = &GridItem{Weight: pane.Ratio, Min: pane.Min}
+	}
+	_, length := self.axis(self.area())
+	return gridSizes(items, length, 1)
+}
+
+// dividers returns the positions of the dividers along the axis of the SplitPane.
+func (self *SplitPane) dividers() []int {
+	position, _ := self.axis(self.area())
+	sizes := self.sizes()
+	dividers := make([]int, 0, len(sizes))
+	for _, size := range sizes[:MaxInt(len(sizes)-1, 0)] {
+		position += size
+		dividers = append(dividers, position)
+		position++
+	}
+	return dividers
+}
+
+// rect converts a start and length along the axis of the SplitPane to a rectangle of the area.
+func (self *SplitPane) rect(start, length int) image.Rectangle {
+	area := self.area()
+	if self.Direction == SplitVertical {
+		return image.Rect(area.Min.X, start, area.Max.X, start+length)
+	}
+	return image.Rect(start, area.Min.Y, start+length, area.Max.Y)
+}
+
+// MoveDivider moves the divider after the i-th pane to position, along the axis of the SplitPane,
+// keeping the panes on both sides above their Min, and calls OnResize.
+func (self *SplitPane) MoveDivider(i int, position int) {
+	if i < 0 || i >= len(self.Panes)-1 {
+		return
+	}
+	sizes := self.sizes()
+	start, _ := self.axis(self.area())
+	for _, size := range sizes[:i] {
+		start += size + 1
+	}
+	total := sizes[i] + sizes[i+1]
+	if total == 0 {
+		return
+	}
+	first, second := self.Panes[i], self.Panes[i+1]
+	size := MaxInt(MinInt(position-start, total-second.Min), first.Min)
+	size = MaxInt(MinInt(size, total), 0)
+
+	// both panes share their ratios in proportion to their new sizes, so that the other panes keep theirs
+	ratios := self.Ratios()
+	shared := ratios[i] + ratios[i+1]
+	for j, pane := range self.Panes {
+		pane.Ratio = ratios[j]
+	}
+	first.Ratio = shared * float64(size) / float64(total)
+	second.Ratio = shared * float64(total-size) / float64(total)
+	if self.OnResize != nil {
+		self.OnResize(self.Ratios())
+	}
+}
+
+func (self *SplitPane) Draw(buf *Buffer) {
+	self.Block.Draw(buf)
+	start, _ := self.axis(self.area())
+	for i, size := range self.sizes() {
+		pane := self.Panes[i]
+		rect := self.rect(start, size)
+		pane.Lock()
+		pane.SetRect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y)
+		pane.Draw(buf.Sub(rect))
+		pane.Unlock()
+		start += size + 1
+	}
+
+	for i, position := range self.dividers() {
+		style := self.DividerStyle
+		if i == self.selectedDivider && (self.dragging || self.Focused()) {
+			style = self.SelectedDividerStyle
+		}
+		cell := Cell{VERTICAL_LINE, style}
+		if self.Direction == SplitVertical {
+			cell.Rune = HORIZONTAL_LINE
+		}
+		buf.Fill(cell, self.rect(position, 1))
+	}
+}
+
+// dividerAt returns the index of the divider at a point, or -1.
+func (self *SplitPane) dividerAt(point image.Point) int {
+	for i, position := range self.dividers() {
+		if point.In(self.rect(position, 1)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleEvent implements the Focusable interface: dragging a divider moves it, and while
+// the SplitPane is focused, [ and ] select the previous and next divider and the arrow keys
+// along its axis move the selected one.
+func (self *SplitPane) HandleEvent(e Event) bool {
+	if len(self.Panes) < 2 {
+		return false
+	}
+	self.selectedDivider = MinInt(self.selectedDivider, len(self.Panes)-2)
+	previous, next := "<Left>", "<Right>"
+	if self.Direction == SplitVertical {
+		previous, next = "<Up>", "<Down>"
+	}
+
+	switch e.Type {
+	case MouseEvent:
+		m, _ := e.Payload.(Mouse)
+		position := m.X
+		if self.Direction == SplitVertical {
+			position = m.Y
+		}
+		switch {
+		case m.Button == MouseButtonLeft && m.Action == MouseActionPress:
+			i := self.dividerAt(image.Pt(m.X, m.Y))
+			if i < 0 {
+				return false
+			}
+			self.selectedDivider, self.dragging = i, true
+		case m.Action == MouseActionDrag && self.dragging:
+			self.MoveDivider(self.selectedDivider, position)
+		case m.Action == MouseActionRelease && self.dragging:
+			self.dragging = false
+		default:
+			return false
+		}
+		return true
+	case KeyboardEvent:
+		switch e.ID {
+		case "[":
+			self.selectedDivider = MaxInt(self.selectedDivider-1, 0)
+		case "]":
+			self.selectedDivider = MinInt(self.selectedDivider+1, len(self.Panes)-2)
+		case previous, next:
+			position := self.dividers()[self.selectedDivider] - 1
+			if e.ID == next {
+				position += 2
+			}
+			self.MoveDivider(self.selectedDivider, position)
+		default:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// HitTest returns the pane containing the point, as laid out by the last Draw.
+func (self *SplitPane) HitTest(point image.Point) Drawable {
+	for _, pane := range self.Panes {
+		if point.In(pane.GetRect()) {
+			return pane.Drawable
+		}
+	}
+	return nil
+}
+
+// Focusables returns the SplitPane itself followed by the Focusable panes,
+// including the items of nested containers.
+func (self *SplitPane) Focusables() []Focusable {
+	drawables := make([]Drawable, len(self.Panes))
+	for i, pane := range self.Panes {
+		drawables[i] = pane.Drawable
+	}
+	return append([]Focusable{self}, focusables(drawables)...)
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// newTestSplitPane returns a SplitPane of three panes drawn in a 32x4 area,
+// 10 cells each with dividers at 10 and 21.
+func newTestSplitPane(direction SplitDirection) *SplitPane {
+	split := NewSplitPane(direction, NewBlock(), NewBlock(), NewBlock())
+	if direction == SplitVertical {
+		split.SetRect(0, 0, 4, 32)
+	} else {
+		split.SetRect(0, 0, 32, 4)
+	}
+	split.Draw(NewBuffer(split.Rectangle))
+	return split
+}
+
+// paneSizes draws the SplitPane and returns the sizes of its panes along its axis.
+func paneSizes(split *SplitPane) []int {
+	split.Draw(NewBuffer(split.Rectangle))
+	sizes := []int{}
+	for _, pane := range split.Panes {
+		if split.Direction == SplitVertical {
+			sizes = append(sizes, pane.GetRect().Dy())
+		} else {
+			sizes = append(sizes, pane.GetRect().Dx())
+		}
+	}
+	return sizes
+}
+
+func expectSizes(t *testing.T, split *SplitPane, expected ...int) {
+	t.Helper()
+	sizes := paneSizes(split)
+	for i := range expected {
+		if sizes[i] != expected[i] {
+			t.Errorf("expected sizes %v, got %v", expected, sizes)
+			return
+		}
+	}
+}
+
+func mouseEvent(x, y int, action MouseAction) Event {
+	m := Mouse{X: x, Y: y, Button: MouseButtonLeft, Action: action}
+	if action == MouseActionRelease {
+		m.Button = MouseButtonNone
+	}
+	return Event{Type: MouseEvent, ID: "<MouseLeft>", Payload: m}
+}
+
+func keyEvent(id string) Event {
+	return Event{Type: KeyboardEvent, ID: id}
+}
+
+func TestSplitPaneLayout(t *testing.T) {
+	split := newTestSplitPane(SplitHorizontal)
+	expected := []image.Rectangle{image.Rect(0, 0, 10, 4), image.Rect(11, 0, 21, 4), image.Rect(22, 0, 32, 4)}
+	for i, pane := range split.Panes {
+		if rect := pane.GetRect(); rect != expected[i] {
+			t.Errorf("pane %d: expected %v, got %v", i, expected[i], rect)
+		}
+	}
+	buf := NewBuffer(split.Rectangle)
+	split.Draw(buf)
+	for _, x := range []int{10, 21} {
+		if cell := buf.GetCell(image.Pt(x, 3)); cell.Rune != VERTICAL_LINE {
+			t.Errorf("expected a divider at %d, got %q", x, cell.Rune)
+		}
+	}
+	if hit := split.HitTest(image.Pt(12, 1)); hit != split.Panes[1].Drawable {
+		t.Errorf("expected the second pane to be hit, got %v", hit)
+	}
+	if hit := split.HitTest(image.Pt(10, 1)); hit != nil {
+		t.Errorf("expected no pane to be hit on a divider, got %v", hit)
+	}
+}
+
+func TestSplitPaneMoveDivider(t *testing.T) {
+	split := newTestSplitPane(SplitHorizontal)
+	split.Panes[0].Min = 4
+	split.Panes[1].Min = 3
+	var reported []float64
+	split.OnResize = func(ratios []float64) { reported = ratios }
+
+	testCases := []struct {
+		divider  int
+		position int
+		sizes    []int
+	}{
+		{0, 15, []int{15, 5, 10}},
+		{0, 2, []int{4, 16, 10}},
+		{0, -10, []int{4, 16, 10}},
+		{0, 30, []int{17, 3, 10}},
+		{1, 25, []int{17, 7, 6}},
+		{1, 40, []int{17, 13, 0}},
+	}
+	for _, tc := range testCases {
+		reported = nil
+		split.MoveDivider(tc.divider, tc.position)
+		expectSizes(t, split, tc.sizes...)
+
+		if len(reported) != 3 {
+			t.Fatalf("expected OnResize to be called with 3 ratios, got %v", reported)
+		}
+		for i, size := range tc.sizes {
+			if ratio := float64(size) / 30; math.Abs(reported[i]-ratio) > 1e-9 {
+				t.Errorf("expected ratios matching %v, got %v", tc.sizes, reported)
+				break
+			}
+		}
+	}
+
+	reported = nil
+	split.MoveDivider(2, 5)
+	split.MoveDivider(-1, 5)
+	if reported != nil {
+		t.Errorf("expected invalid dividers to be ignored, got %v", reported)
+	}
+
+	// the reported ratios restore the layout
+	ratios := split.Ratios()
+	restored := newTestSplitPane(SplitHorizontal)
+	restored.SetRatios(ratios...)
+	expectSizes(t, restored, 17, 13, 0)
+}
+
+func TestSplitPaneMouse(t *testing.T) {
+	split := newTestSplitPane(SplitHorizontal)
+	resized := 0
+	split.OnResize = func([]float64) { resized++ }
+
+	if split.HandleEvent(mouseEvent(5, 1, MouseActionPress)) {
+		t.Error("expected a press outside of the dividers to be ignored")
+	}
+	if split.HandleEvent(mouseEvent(15, 1, MouseActionDrag)) {
+		t.Error("expected a drag without a pressed divider to be ignored")
+	}
+	if !split.HandleEvent(mouseEvent(21, 2, MouseActionPress)) {
+		t.Fatal("expected a press on a divider to be used")
+	}
+	buf := NewBuffer(split.Rectangle)
+	split.Draw(buf)
+	if style := buf.GetCell(image.Pt(21, 0)).Style; style != split.SelectedDividerStyle {
+		t.Errorf("expected the dragged divider to be selected, got %v", style)
+	}
+	if !split.HandleEvent(mouseEvent(25, 2, MouseActionDrag)) {
+		t.Error("expected a drag to be used")
+	}
+	expectSizes(t, split, 10, 14, 6)
+	split.HandleEvent(mouseEvent(18, 0, MouseActionDrag))
+	expectSizes(t, split, 10, 7, 13)
+	if !split.HandleEvent(mouseEvent(18, 0, MouseActionRelease)) {
+		t.Error("expected the release to be used")
+	}
+	if split.HandleEvent(mouseEvent(2, 0, MouseActionDrag)) {
+		t.Error("expected drags to be ignored after the release")
+	}
+	expectSizes(t, split, 10, 7, 13)
+	if resized != 2 {
+		t.Errorf("expected OnResize to be called for each drag, got %d calls", resized)
+	}
+	if split.HandleEvent(mouseEvent(18, 0, MouseActionRelease)) {
+		t.Error("expected a release without a drag to be ignored")
+	}
+
+	vertical := newTestSplitPane(SplitVertical)
+	vertical.HandleEvent(mouseEvent(1, 10, MouseActionPress))
+	vertical.HandleEvent(mouseEvent(1, 5, MouseActionDrag))
+	expectSizes(t, vertical, 5, 15, 10)
+}
+
+func TestSplitPaneKeys(t *testing.T) {
+	split := newTestSplitPane(SplitHorizontal)
+	testCases := []struct {
+		id    string
+		used  bool
+		sizes []int
+	}{
+		{"<Right>", true, []int{11, 9, 10}},
+		{"]", true, []int{11, 9, 10}},
+		{"]", true, []int{11, 9, 10}},
+		{"<Right>", true, []int{11, 10, 9}},
+		{"[", true, []int{11, 10, 9}},
+		{"<Left>", true, []int{10, 11, 9}},
+		{"[", true, []int{10, 11, 9}},
+		{"<Left>", true, []int{9, 12, 9}},
+		{"<Down>", false, []int{9, 12, 9}},
+		{"x", false, []int{9, 12, 9}},
+	}
+	for _, tc := range testCases {
+		if used := split.HandleEvent(keyEvent(tc.id)); used != tc.used {
+			t.Errorf("%s: expected used %v, got %v", tc.id, tc.used, used)
+		}
+		expectSizes(t, split, tc.sizes...)
+	}
+
+	vertical := newTestSplitPane(SplitVertical)
+	if vertical.HandleEvent(keyEvent("<Right>")) {
+		t.Error("expected <Right> to be ignored by a vertical SplitPane")
+	}
+	vertical.HandleEvent(keyEvent("<Down>"))
+	expectSizes(t, vertical, 11, 9, 10)
+
+	single := NewSplitPane(SplitHorizontal, NewBlock())
+	if single.HandleEvent(keyEvent("<Right>")) {
+		t.Error("expected a SplitPane without dividers to ignore events")
+	}
+}
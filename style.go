@@ -1,6 +1,12 @@
 package termui
 
-// Color is an integer from -1 to 255
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Color is an integer from -1 to 255 or a 24-bit color created with ColorRGB
 // -1 = ColorClear
 // 0-255 = Xterm colors
 type Color int
@@ -20,16 +26,39 @@ const (
 	ColorWhite   Color = 7
 )
 
+// Modifier is a set of text attributes which can be combined with |.
+// Backends which can't display a Modifier fall back to the closest one they support, or ignore it.
 type Modifier uint
 
 const (
 	// ModifierClear clears any modifiers
-	ModifierClear     Modifier = 0
-	ModifierBold      Modifier = 1 << 9
-	ModifierUnderline Modifier = 1 << 10
-	ModifierReverse   Modifier = 1 << 11
+	ModifierClear           Modifier = 0
+	ModifierBold            Modifier = 1 << 9
+	ModifierUnderline       Modifier = 1 << 10
+	ModifierReverse         Modifier = 1 << 11
+	ModifierItalic          Modifier = 1 << 12
+	ModifierDim             Modifier = 1 << 13
+	ModifierStrikethrough   Modifier = 1 << 14
+	ModifierBlink           Modifier = 1 << 15
+	ModifierDoubleUnderline Modifier = 1 << 16
 )
 
+// modifierFallbacks are used in place of a Modifier which a backend can't display.
+var modifierFallbacks = map[Modifier]Modifier{
+	ModifierDoubleUnderline: ModifierUnderline,
+}
+
+// Fallback returns the Modifier restricted to the supported modifiers,
+// replacing the unsupported ones by a supported fallback when there is one.
+func (self Modifier) Fallback(supported Modifier) Modifier {
+	for modifier, fallback := range modifierFallbacks {
+		if self&modifier != 0 && supported&modifier == 0 {
+			self |= fallback
+		}
+	}
+	return self & supported
+}
+
 // Style represents the style of one terminal cell
 type Style struct {
 	Fg       Color
@@ -44,6 +73,19 @@ var StyleClear = Style{
 	Modifier: ModifierClear,
 }
 
+// Overlay returns the style with the colors of other, unless they are ColorClear,
+// and the modifiers of both.
+func (self Style) Overlay(other Style) Style {
+	if other.Fg != ColorClear {
+		self.Fg = other.Fg
+	}
+	if other.Bg != ColorClear {
+		self.Bg = other.Bg
+	}
+	self.Modifier |= other.Modifier
+	return self
+}
+
 // NewStyle takes 1 to 3 arguments
 // 1st argument = Fg
 // 2nd argument = optional Bg
@@ -63,3 +105,45 @@ func NewStyle(fg Color, args ...interface{}) Style {
 		modifier,
 	}
 }
+
+// String returns the Style in the `fg:<color>,bg:<color>,mod:<attribute>` format
+// understood by `ParseStyles`.
+func (self Style) String() string {
+	items := []string{}
+	if self.Fg != ColorClear {
+		items = append(items, tokenFg+tokenValueSeparator+colorString(self.Fg))
+	}
+	if self.Bg != ColorClear {
+		items = append(items, tokenBg+tokenValueSeparator+colorString(self.Bg))
+	}
+	modifiers := []string{}
+	for name, modifier := range modifierMap {
+		if self.Modifier&modifier != 0 {
+			modifiers = append(modifiers, name)
+		}
+	}
+	if len(modifiers) > 0 {
+		sort.Strings(modifiers)
+		items = append(items, tokenModifier+tokenValueSeparator+strings.Join(modifiers, tokenModifierSeparator))
+	}
+	return strings.Join(items, tokenItemSeparator)
+}
+
+func colorString(color Color) string {
+	if color.IsRGB() {
+		r, g, b := color.RGB()
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	names := make([]string, 0, len(StyleParserColorMap))
+	for name := range StyleParserColorMap {
+		names = append(names, name)
+	}
+	// sort the names so that colors with several names always get the same one
+	sort.Strings(names)
+	for _, name := range names {
+		if StyleParserColorMap[name] == color {
+			return name
+		}
+	}
+	return fmt.Sprint(int(color))
+}
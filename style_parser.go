@@ -5,6 +5,8 @@
 package termui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -13,22 +15,17 @@ const (
 	tokenBg       = "bg"
 	tokenModifier = "mod"
 
-	tokenItemSeparator  = ","
-	tokenValueSeparator = ":"
+	tokenItemSeparator     = ","
+	tokenValueSeparator    = ":"
+	tokenModifierSeparator = "|"
 
 	tokenBeginStyledText = '['
 	tokenEndStyledText   = ']'
 
 	tokenBeginStyle = '('
 	tokenEndStyle   = ')'
-)
-
-type parserState uint
 
-const (
-	parserStateDefault parserState = iota
-	parserStateStyleItems
-	parserStateStyledText
+	tokenEscape = '\\'
 )
 
 // StyleParserColorMap can be modified to add custom color parsing to text
@@ -45,112 +42,243 @@ var StyleParserColorMap = map[string]Color{
 }
 
 var modifierMap = map[string]Modifier{
-	"bold":      ModifierBold,
-	"underline": ModifierUnderline,
-	"reverse":   ModifierReverse,
+	"bold":            ModifierBold,
+	"underline":       ModifierUnderline,
+	"reverse":         ModifierReverse,
+	"italic":          ModifierItalic,
+	"dim":             ModifierDim,
+	"strikethrough":   ModifierStrikethrough,
+	"blink":           ModifierBlink,
+	"doubleunderline": ModifierDoubleUnderline,
+}
+
+// readColor translates a color name from StyleParserColorMap, a 256 color index like `208`
+// or a hex color like `#ff8800` to a Color.
+// Invalid colors are reported as an error.
+func readColor(s string) (Color, error) {
+	if color, ok := StyleParserColorMap[s]; ok {
+		return color, nil
+	}
+	if strings.HasPrefix(s, "#") {
+		return parseHexColor(s)
+	}
+	if index, err := strconv.Atoi(s); err == nil {
+		if index < 0 || index > 255 {
+			return ColorClear, fmt.Errorf("color index %d out of range 0-255", index)
+		}
+		return Color(index), nil
+	}
+	return ColorClear, fmt.Errorf("unknown color %q", s)
+}
+
+// readModifier translates a list of modifier names like `bold|underline` to a Modifier.
+// Unknown names are ignored and reported as an error.
+func readModifier(s string) (Modifier, error) {
+	var err error
+	modifier := ModifierClear
+	for _, name := range strings.Split(s, tokenModifierSeparator) {
+		m, ok := modifierMap[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("unknown modifier %q", name)
+		}
+		modifier |= m
+	}
+	return modifier, err
 }
 
-// readStyle translates an []rune like `fg:red,mod:bold,bg:white` to a style
-func readStyle(runes []rune, defaultStyle Style) Style {
-	style := defaultStyle
-	split := strings.Split(string(runes), tokenItemSeparator)
-	for _, item := range split {
+// readStyle translates a string like `fg:red,mod:bold,bg:white` to a style.
+// Attributes which are not given are inherited from the parent style.
+// Modifiers of the parent are replaced by the first mod item, and later mod items are combined with it.
+// Invalid items are skipped and the first one is reported as an error.
+func readStyle(s string, parent Style) (Style, error) {
+	var err error
+	fail := func(e error) {
+		if err == nil {
+			err = e
+		}
+	}
+
+	style := parent
+	modifierSet := false
+	for _, item := range strings.Split(s, tokenItemSeparator) {
 		pair := strings.Split(item, tokenValueSeparator)
-		if len(pair) == 2 {
-			switch pair[0] {
-			case tokenFg:
-				style.Fg = StyleParserColorMap[pair[1]]
-			case tokenBg:
-				style.Bg = StyleParserColorMap[pair[1]]
-			case tokenModifier:
-				style.Modifier = modifierMap[pair[1]]
+		if len(pair) != 2 {
+			fail(fmt.Errorf("invalid style item %q", item))
+			continue
+		}
+		switch pair[0] {
+		case tokenFg, tokenBg:
+			color, e := readColor(pair[1])
+			if e != nil {
+				fail(e)
+				continue
+			}
+			if pair[0] == tokenFg {
+				style.Fg = color
+			} else {
+				style.Bg = color
+			}
+		case tokenModifier:
+			modifier, e := readModifier(pair[1])
+			if e != nil {
+				fail(e)
 			}
+			if !modifierSet {
+				style.Modifier = ModifierClear
+				modifierSet = true
+			}
+			style.Modifier |= modifier
+		default:
+			fail(fmt.Errorf("unknown style item %q", pair[0]))
 		}
 	}
-	return style
+	return style, err
 }
 
-// ParseStyles parses a string for embedded Styles and returns []Cell with the correct styling.
-// Uses defaultStyle for any text without an embedded style.
-// Syntax is of the form [text](fg:<color>,mod:<attribute>,bg:<color>).
-// Ordering does not matter. All fields are optional.
-func ParseStyles(s string, defaultStyle Style) []Cell {
-	cells := []Cell{}
-	runes := []rune(s)
-	state := parserStateDefault
-	styledText := []rune{}
-	styleItems := []rune{}
-	squareCount := 0
-
-	reset := func() {
-		styledText = []rune{}
-		styleItems = []rune{}
-		state = parserStateDefault
-		squareCount = 0
+// styledItem is either a rune or a styled span of the parsed text.
+type styledItem struct {
+	rune rune
+	span *styledSpan
+}
+
+// styledSpan is a [text](style) span of the parsed text.
+type styledSpan struct {
+	items []styledItem
+	style string
+	// pos is the position of the style in the parsed text
+	pos int
+}
+
+// styleParser turns a string using the `ParseStyles` markup into a tree of styledItems.
+// Malformed markup is kept as text and the first error is recorded.
+type styleParser struct {
+	runes []rune
+	pos   int
+	err   error
+}
+
+func (self *styleParser) fail(pos int, format string, args ...interface{}) {
+	if self.err == nil {
+		self.err = fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), pos)
 	}
+}
+
+func isStyleToken(r rune) bool {
+	return r == tokenBeginStyledText || r == tokenEndStyledText || r == tokenEscape
+}
 
-	rollback := func() {
-		cells = append(cells, RunesToStyledCells(styledText, defaultStyle)...)
-		cells = append(cells, RunesToStyledCells(styleItems, defaultStyle)...)
-		reset()
+// parseItems parses runes until the end of the text or, if depth > 0,
+// the ']' closing the current span. It reports whether the span was closed.
+func (self *styleParser) parseItems(depth int) ([]styledItem, bool) {
+	items := []styledItem{}
+	for self.pos < len(self.runes) {
+		r := self.runes[self.pos]
+		switch {
+		case r == tokenEscape && self.pos+1 < len(self.runes) && isStyleToken(self.runes[self.pos+1]):
+			items = append(items, styledItem{rune: self.runes[self.pos+1]})
+			self.pos += 2
+		case r == tokenBeginStyledText:
+			start := self.pos
+			self.pos++
+			items = append(items, self.parseSpan(start, depth+1)...)
+		case r == tokenEndStyledText && depth > 0:
+			self.pos++
+			return items, true
+		default:
+			if r == tokenEndStyledText {
+				self.fail(self.pos, "unexpected %q", r)
+			}
+			items = append(items, styledItem{rune: r})
+			self.pos++
+		}
 	}
+	return items, false
+}
 
-	// chop first and last runes
-	chop := func(s []rune) []rune {
-		return s[1 : len(s)-1]
+// parseSpan parses a span following the '[' at start.
+// If the span is not followed by a style, its text is kept along with its brackets.
+func (self *styleParser) parseSpan(start, depth int) []styledItem {
+	children, closed := self.parseItems(depth)
+	items := append([]styledItem{{rune: tokenBeginStyledText}}, children...)
+	if !closed {
+		self.fail(start, "unterminated %q", tokenBeginStyledText)
+		return items
 	}
+	items = append(items, styledItem{rune: tokenEndStyledText})
 
-	for i, _rune := range runes {
-		switch state {
-		case parserStateDefault:
-			if _rune == tokenBeginStyledText {
-				state = parserStateStyledText
-				squareCount = 1
-				styledText = append(styledText, _rune)
-			} else {
-				cells = append(cells, Cell{_rune, defaultStyle})
-			}
-		case parserStateStyledText:
-			switch {
-			case squareCount == 0:
-				switch _rune {
-				case tokenBeginStyle:
-					state = parserStateStyleItems
-					styleItems = append(styleItems, _rune)
-				default:
-					rollback()
-					switch _rune {
-					case tokenBeginStyledText:
-						state = parserStateStyledText
-						squareCount = 1
-						styleItems = append(styleItems, _rune)
-					default:
-						cells = append(cells, Cell{_rune, defaultStyle})
-					}
-				}
-			case len(runes) == i+1:
-				rollback()
-				styledText = append(styledText, _rune)
-			case _rune == tokenBeginStyledText:
-				squareCount++
-				styledText = append(styledText, _rune)
-			case _rune == tokenEndStyledText:
-				squareCount--
-				styledText = append(styledText, _rune)
-			default:
-				styledText = append(styledText, _rune)
-			}
-		case parserStateStyleItems:
-			styleItems = append(styleItems, _rune)
-			if _rune == tokenEndStyle {
-				style := readStyle(chop(styleItems), defaultStyle)
-				cells = append(cells, RunesToStyledCells(chop(styledText), style)...)
-				reset()
-			} else if len(runes) == i+1 {
-				rollback()
+	if self.pos >= len(self.runes) || self.runes[self.pos] != tokenBeginStyle {
+		self.fail(self.pos-1, "%q not followed by a style", tokenEndStyledText)
+		return items
+	}
+	for end := self.pos + 1; end < len(self.runes); end++ {
+		if self.runes[end] == tokenEndStyle {
+			span := &styledSpan{
+				items: children,
+				style: string(self.runes[self.pos+1 : end]),
+				pos:   self.pos + 1,
 			}
+			self.pos = end + 1
+			return []styledItem{{span: span}}
+		}
+	}
+	self.fail(self.pos, "unterminated %q", tokenBeginStyle)
+	return items
+}
+
+// cells converts the parsed items to cells, styling spans based on the style of their parent.
+func (self *styleParser) cells(items []styledItem, style Style) []Cell {
+	cells := []Cell{}
+	for _, item := range items {
+		if item.span == nil {
+			cells = append(cells, Cell{item.rune, style})
+			continue
 		}
+		spanStyle, err := readStyle(item.span.style, style)
+		if err != nil {
+			self.fail(item.span.pos, "%v", err)
+		}
+		cells = append(cells, self.cells(item.span.items, spanStyle)...)
 	}
+	return cells
+}
+
+func parseStyles(s string, defaultStyle Style) ([]Cell, error) {
+	parser := &styleParser{runes: []rune(s)}
+	items, _ := parser.parseItems(0)
+	cells := parser.cells(items, defaultStyle)
+	return cells, parser.err
+}
 
+// ParseStyles parses a string for embedded Styles and returns []Cell with the correct styling.
+// Uses defaultStyle for any text without an embedded style.
+// Syntax is of the form [text](fg:<color>,mod:<attribute>,bg:<color>).
+// Colors are either names from StyleParserColorMap, 256 color indexes like 208,
+// or hex values like #ff8800 or #f80.
+// Several modifiers can be combined with |, e.g. mod:bold|underline.
+// Ordering does not matter. All fields are optional.
+// Spans can be nested, in which case they inherit the style of their parent.
+// Literal brackets and backslashes can be escaped with a backslash: \[, \] and \\.
+// Malformed markup is rendered as plain text, see ParseStylesStrict to detect it.
+func ParseStyles(s string, defaultStyle Style) []Cell {
+	cells, _ := parseStyles(s, defaultStyle)
 	return cells
 }
+
+// ParseStylesStrict is like ParseStyles but also returns an error describing the first
+// malformed span, unescaped bracket, or invalid style item of the string.
+// The returned cells are the same as the ones returned by ParseStyles.
+func ParseStylesStrict(s string, defaultStyle Style) ([]Cell, error) {
+	return parseStyles(s, defaultStyle)
+}
+
+// EscapeStyles escapes the brackets and backslashes of a string so that it is displayed as is by ParseStyles.
+func EscapeStyles(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if isStyleToken(r) {
+			sb.WriteRune(tokenEscape)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
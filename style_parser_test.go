@@ -0,0 +1,129 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"reflect"
+	"testing"
+)
+
+// styledText is a run of text with the same style.
+type styledText struct {
+	text  string
+	style Style
+}
+
+// styledTexts groups the cells into runs of the same style.
+func styledTexts(cells []Cell) []styledText {
+	texts := []styledText{}
+	for _, cell := range cells {
+		if len(texts) > 0 && texts[len(texts)-1].style == cell.Style {
+			texts[len(texts)-1].text += string(cell.Rune)
+			continue
+		}
+		texts = append(texts, styledText{string(cell.Rune), cell.Style})
+	}
+	return texts
+}
+
+func TestParseStyles(t *testing.T) {
+	def := NewStyle(ColorWhite)
+	red := NewStyle(ColorRed)
+	testCases := []struct {
+		name     string
+		input    string
+		expected []styledText
+	}{
+		{"plain", "hello", []styledText{{"hello", def}}},
+		{"span", "a [b](fg:red) c", []styledText{{"a ", def}, {"b", red}, {" c", def}}},
+		{"all items", "[b](bg:blue,mod:bold,fg:red)", []styledText{
+			{"b", NewStyle(ColorRed, ColorBlue, ModifierBold)},
+		}},
+		{"nested", "[a [b](bg:blue) c](fg:red)", []styledText{
+			{"a ", red}, {"b", NewStyle(ColorRed, ColorBlue)}, {" c", red},
+		}},
+		{"nested modifiers", "[a [b](mod:italic) c](mod:bold|underline)", []styledText{
+			{"a ", NewStyle(ColorWhite, ColorClear, ModifierBold|ModifierUnderline)},
+			{"b", NewStyle(ColorWhite, ColorClear, ModifierItalic)},
+			{" c", NewStyle(ColorWhite, ColorClear, ModifierBold|ModifierUnderline)},
+		}},
+		{"repeated mod items", "[a](mod:bold,mod:dim)", []styledText{
+			{"a", NewStyle(ColorWhite, ColorClear, ModifierBold|ModifierDim)},
+		}},
+		{"256 colors", "[a](fg:208)", []styledText{{"a", NewStyle(Color(208))}}},
+		{"hex colors", "[a](fg:#ff8800,bg:#f80)", []styledText{
+			{"a", NewStyle(ColorRGB(0xff, 0x88, 0x00), ColorRGB(0xff, 0x88, 0x00))},
+		}},
+		{"escapes", `\[a\](fg:red) \\`, []styledText{{`[a](fg:red) \`, def}}},
+		{"escape in span", `[\]](fg:red)`, []styledText{{"]", red}}},
+		{"backslash before text", `a\b`, []styledText{{`a\b`, def}}},
+		{"unknown color keeps the parent color", "[a [b](fg:nope,bg:blue)](fg:red)", []styledText{
+			{"a ", red}, {"b", NewStyle(ColorRed, ColorBlue)},
+		}},
+		{"invalid color index", "[a](fg:300)", []styledText{{"a", def}}},
+		{"invalid hex color", "[a](fg:#12)", []styledText{{"a", def}}},
+		{"unknown modifier", "[a](mod:bold|nope)", []styledText{
+			{"a", NewStyle(ColorWhite, ColorClear, ModifierBold)},
+		}},
+		{"unknown item", "[a](size:2,fg:red)", []styledText{{"a", red}}},
+		{"no style", "[a] b", []styledText{{"[a] b", def}}},
+		{"unterminated span", "[a(fg:red)", []styledText{{"[a(fg:red)", def}}},
+		{"unterminated style", "[a](fg:red", []styledText{{"[a](fg:red", def}}},
+		{"unexpected bracket", "a]b", []styledText{{"a]b", def}}},
+	}
+	for _, tc := range testCases {
+		cells := ParseStyles(tc.input, def)
+		if texts := styledTexts(cells); !reflect.DeepEqual(texts, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, texts)
+		}
+		strictCells, _ := ParseStylesStrict(tc.input, def)
+		if !reflect.DeepEqual(strictCells, cells) {
+			t.Errorf("%s: ParseStylesStrict returned different cells", tc.name)
+		}
+	}
+}
+
+func TestParseStylesStrict(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"a [b](fg:red) \\[c\\]", ""},
+		{"[a [b](bg:blue)](fg:red)", ""},
+		{"[a](fg:nope)", `unknown color "nope" at position 4`},
+		{"[a](fg:300)", "color index 300 out of range 0-255 at position 4"},
+		{"[a](mod:bold|nope)", `unknown modifier "nope" at position 4`},
+		{"[a](fg)", `invalid style item "fg" at position 4`},
+		{"[a](size:2)", `unknown style item "size" at position 4`},
+		{"[a] b", `']' not followed by a style at position 2`},
+		{"x [a(fg:red)", `unterminated '[' at position 2`},
+		{"[a](fg:red", `unterminated '(' at position 3`},
+		{"a]b", `unexpected ']' at position 1`},
+		{"[a](fg:red,bg:nope) [b](fg:nope)", `unknown color "nope" at position 4`},
+	}
+	for _, tc := range testCases {
+		_, err := ParseStylesStrict(tc.input, StyleClear)
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+		if message != tc.expected {
+			t.Errorf("%q: expected error %q, got %q", tc.input, tc.expected, message)
+		}
+	}
+}
+
+func TestEscapeStyles(t *testing.T) {
+	for _, s := range []string{"plain", "[a](fg:red)", `a\b`, `]\[`} {
+		escaped := EscapeStyles(s)
+		cells, err := ParseStylesStrict(escaped, StyleClear)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", escaped, err)
+		}
+		if text := styledTexts(cells); len(text) != 1 || text[0].text != s {
+			t.Errorf("%q: expected %q, got %v", escaped, s, text)
+		}
+	}
+}
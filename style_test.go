@@ -0,0 +1,40 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"testing"
+)
+
+func TestStyleString(t *testing.T) {
+	testCases := []struct {
+		style    Style
+		expected string
+	}{
+		{StyleClear, ""},
+		{NewStyle(ColorRed), "fg:red"},
+		{NewStyle(ColorClear, ColorBlue), "bg:blue"},
+		{NewStyle(Color(208), ColorRGB(255, 136, 0)), "fg:208,bg:#ff8800"},
+		{NewStyle(ColorWhite, ColorBlack, ModifierUnderline|ModifierBold), "fg:white,bg:black,mod:bold|underline"},
+	}
+	for _, tc := range testCases {
+		if s := tc.style.String(); s != tc.expected {
+			t.Errorf("%+v: expected %q, got %q", tc.style, tc.expected, s)
+		}
+	}
+}
+
+func TestStyleStringColorAlias(t *testing.T) {
+	StyleParserColorMap["scarlet"] = ColorRed
+	StyleParserColorMap["crimson"] = ColorRed
+	defer delete(StyleParserColorMap, "scarlet")
+	defer delete(StyleParserColorMap, "crimson")
+
+	for i := 0; i < 20; i++ {
+		if s := NewStyle(ColorRed).String(); s != "fg:crimson" {
+			t.Fatalf("expected %q, got %q", "fg:crimson", s)
+		}
+	}
+}
@@ -19,6 +19,7 @@ const (
 	VERTICAL_RIGHT  = '+'
 	HORIZONTAL_UP   = '+'
 	HORIZONTAL_DOWN = '+'
+	CROSS           = '+'
 
 	QUOTA_LEFT  = '<'
 	QUOTA_RIGHT = '>'
@@ -0,0 +1,101 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	terminfoMagic         = 0432
+	terminfoExtendedMagic = 01036
+
+	// terminfoMaxColors is the index of the max_colors capability in the numbers section.
+	terminfoMaxColors = 13
+)
+
+// terminfoDirs returns the directories searched for terminfo entries, in order.
+func terminfoDirs() []string {
+	dirs := []string{}
+	if dir := os.Getenv("TERMINFO"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	for _, dir := range strings.Split(os.Getenv("TERMINFO_DIRS"), ":") {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, "/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo")
+}
+
+// readTerminfo returns the compiled terminfo entry of a terminal.
+func readTerminfo(term string) ([]byte, error) {
+	if term == "" {
+		return nil, fmt.Errorf("TERM is not set")
+	}
+	for _, dir := range terminfoDirs() {
+		// entries are stored under their first letter, or its hex code on darwin
+		for _, sub := range []string{term[:1], fmt.Sprintf("%x", term[0])} {
+			data, err := ioutil.ReadFile(filepath.Join(dir, sub, term))
+			if err == nil {
+				return data, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no terminfo entry for %q", term)
+}
+
+// terminfoColors returns the max_colors capability of a terminal.
+func terminfoColors(term string) (int, bool) {
+	data, err := readTerminfo(term)
+	if err != nil || len(data) < 12 {
+		return 0, false
+	}
+
+	header := make([]int, 6)
+	for i := range header {
+		header[i] = int(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	numberSize := 2
+	switch header[0] {
+	case terminfoMagic:
+	case terminfoExtendedMagic:
+		numberSize = 4
+	default:
+		return 0, false
+	}
+
+	namesSize, boolCount, numberCount := header[1], header[2], header[3]
+	if numberCount <= terminfoMaxColors {
+		return 0, false
+	}
+	offset := 12 + namesSize + boolCount
+	if offset%2 != 0 {
+		offset++
+	}
+	offset += terminfoMaxColors * numberSize
+	if offset+numberSize > len(data) {
+		return 0, false
+	}
+
+	var colors int
+	if numberSize == 2 {
+		colors = int(int16(binary.LittleEndian.Uint16(data[offset:])))
+	} else {
+		colors = int(int32(binary.LittleEndian.Uint32(data[offset:])))
+	}
+	if colors < 0 {
+		return 0, false
+	}
+	return colors, true
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+/*
+Package termuitest provides golden file snapshot testing for termui widgets.
+
+A Drawable is rendered into a fixed-size Buffer and compared against a golden file
+stored in the testdata directory of the package under test:
+
+	func TestTable(t *testing.T) {
+		table := widgets.NewTable()
+		table.Rows = [][]string{{"a", "b"}, {"c", "d"}}
+		termuitest.AssertGolden(t, "table", table, 20, 6)
+	}
+
+Run `go test -termuitest.update` to write the golden files from the current output,
+or set Update from a flag of the tests.
+*/
+package termuitest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rw "github.com/mattn/go-runewidth"
+
+	ui "github.com/jcalmat/termui/v3"
+)
+
+// Update makes the assertions write the golden files instead of comparing them.
+// It is set by the `-termuitest.update` flag.
+var Update bool
+
+func init() {
+	flag.BoolVar(&Update, "termuitest.update", false, "update termuitest golden files")
+}
+
+const (
+	stylesHeader = "-- styles --"
+	legendHeader = "-- legend --"
+
+	// clearStyleKey marks cells using StyleClear in the style annotation layer.
+	clearStyleKey = '.'
+)
+
+// styleKeys are assigned to the styles of a snapshot in order of appearance.
+var styleKeys = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+// Render sets the rectangle of the Drawable to (0, 0, width, height) and draws it into a new Buffer.
+func Render(d ui.Drawable, width, height int) *ui.Buffer {
+	buf := ui.NewBuffer(image.Rect(0, 0, width, height))
+	d.SetRect(0, 0, width, height)
+	d.Lock()
+	d.Draw(buf)
+	d.Unlock()
+	return buf
+}
+
+// rows returns the cells of the Buffer split in rows,
+// skipping the cells covered by the right half of wide runes.
+func rows(buf *ui.Buffer) [][]ui.Cell {
+	rows := [][]ui.Cell{}
+	for y := buf.Min.Y; y < buf.Max.Y; y++ {
+		row := []ui.Cell{}
+		for x := buf.Min.X; x < buf.Max.X; x++ {
+			cell := buf.GetCell(image.Pt(x, y))
+			row = append(row, cell)
+			if rw.RuneWidth(cell.Rune) == 2 {
+				x++
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Text returns the runes of the Buffer, one line per row.
+func Text(buf *ui.Buffer) string {
+	lines := []string{}
+	for _, row := range rows(buf) {
+		lines = append(lines, ui.CellsToString(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StyledText returns the runes of the Buffer followed by a style annotation layer.
+// The annotation layer has one key per cell and a legend mapping each key to its Style.
+// Cells using StyleClear are annotated with '.'.
+func StyledText(buf *ui.Buffer) string {
+	keys := map[ui.Style]rune{}
+	legend := []string{}
+	annotations := []string{}
+	for _, row := range rows(buf) {
+		var sb strings.Builder
+		for _, cell := range row {
+			if cell.Style == ui.StyleClear {
+				sb.WriteRune(clearStyleKey)
+				continue
+			}
+			key, ok := keys[cell.Style]
+			if !ok {
+				key = '?'
+				if len(keys) < len(styleKeys) {
+					key = styleKeys[len(keys)]
+					legend = append(legend, fmt.Sprintf("%c %s", key, cell.Style))
+				}
+				keys[cell.Style] = key
+			}
+			sb.WriteRune(key)
+		}
+		annotations = append(annotations, sb.String())
+	}
+	return strings.Join([]string{
+		Text(buf),
+		stylesHeader,
+		strings.Join(annotations, "\n"),
+		legendHeader,
+		strings.Join(legend, "\n"),
+	}, "\n")
+}
+
+// AssertGolden renders the Drawable and compares its runes against testdata/<name>.golden.
+func AssertGolden(t testing.TB, name string, d ui.Drawable, width, height int) {
+	t.Helper()
+	assertGolden(t, name, Text(Render(d, width, height)))
+}
+
+// AssertStyledGolden renders the Drawable and compares its runes and styles against testdata/<name>.golden.
+func AssertStyledGolden(t testing.TB, name string, d ui.Drawable, width, height int) {
+	t.Helper()
+	assertGolden(t, name, StyledText(Render(d, width, height)))
+}
+
+func assertGolden(t testing.TB, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got+"\n"), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run `go test -termuitest.update` to create it: %v", err)
+	}
+	want := strings.TrimSuffix(string(data), "\n")
+	if got != want {
+		t.Errorf("%s does not match the rendered output:\n%s", path, diff(want, got))
+	}
+}
+
+// diff returns the lines which differ between want and got.
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var sb strings.Builder
+	for i := 0; i < ui.MaxInt(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&sb, "line %d:\n-%s\n+%s\n", i+1, w, g)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,151 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termuitest
+
+import (
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	ui "github.com/jcalmat/termui/v3"
+)
+
+// recorder is a testing.TB recording failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (self *recorder) Helper() {}
+
+func (self *recorder) Errorf(format string, args ...interface{}) {
+	self.errors = append(self.errors, fmt.Sprintf(format, args...))
+}
+
+func (self *recorder) Fatalf(format string, args ...interface{}) {
+	self.Errorf(format, args...)
+	self.fatal = true
+	runtime.Goexit()
+}
+
+// record runs fn with a recorder in its own goroutine so that Fatalf can stop it.
+func record(t *testing.T, fn func(tb testing.TB)) *recorder {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r
+}
+
+// inTempDir runs the test in an empty working directory.
+func inTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "termuitest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func newTitledBlock(text string) *ui.Block {
+	block := ui.NewBlock()
+	block.Title = text
+	return block
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	dir := inTempDir(t)
+	Update = true
+	defer func() { Update = false }()
+
+	r := record(t, func(tb testing.TB) {
+		AssertGolden(tb, "block", newTitledBlock("a"), 5, 3)
+	})
+	if len(r.errors) > 0 {
+		t.Fatalf("unexpected errors: %v", r.errors)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "testdata", "block.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "┌─a─┐\n│   │\n└───┘\n"
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestAssertGoldenCompare(t *testing.T) {
+	inTempDir(t)
+	if err := os.MkdirAll("testdata", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join("testdata", "block.golden"), []byte("┌─a─┐\n│   │\n└───┘\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := record(t, func(tb testing.TB) {
+		AssertGolden(tb, "block", newTitledBlock("a"), 5, 3)
+	})
+	if len(r.errors) > 0 {
+		t.Errorf("unexpected errors: %v", r.errors)
+	}
+
+	r = record(t, func(tb testing.TB) {
+		AssertGolden(tb, "block", newTitledBlock("b"), 5, 3)
+	})
+	if len(r.errors) != 1 || r.fatal {
+		t.Fatalf("expected one error, got %v", r.errors)
+	}
+	if !strings.Contains(r.errors[0], "line 1:\n-┌─a─┐\n+┌─b─┐\n") || strings.Contains(r.errors[0], "line 2") {
+		t.Errorf("expected a diff of the first line, got %q", r.errors[0])
+	}
+
+	r = record(t, func(tb testing.TB) {
+		AssertGolden(tb, "missing", newTitledBlock("a"), 5, 3)
+	})
+	if !r.fatal || !strings.Contains(r.errors[0], "go test -termuitest.update") {
+		t.Errorf("expected a missing golden file to be fatal, got %v", r.errors)
+	}
+}
+
+func TestStyledText(t *testing.T) {
+	buf := ui.NewBuffer(image.Rect(0, 0, 4, 2))
+	buf.SetString("ab", ui.NewStyle(ui.ColorRed), image.Pt(0, 0))
+	buf.SetString("世", ui.NewStyle(ui.ColorBlue, ui.ColorClear, ui.ModifierBold), image.Pt(2, 0))
+	buf.SetString("c", ui.NewStyle(ui.ColorRed), image.Pt(1, 1))
+
+	expected := strings.Join([]string{
+		"ab世",
+		" c  ",
+		stylesHeader,
+		"aab",
+		".a..",
+		legendHeader,
+		"a fg:red",
+		"b fg:blue,mod:bold",
+	}, "\n")
+	if s := StyledText(buf); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+}
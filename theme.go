@@ -27,9 +27,11 @@ var StandardStyles = []Style{
 type RootTheme struct {
 	Default Style
 
-	Block BlockTheme
+	Block  BlockTheme
+	Layers LayersTheme
 
 	BarChart        BarChartTheme
+	Dialog          DialogTheme
 	Gauge           GaugeTheme
 	Plot            PlotTheme
 	List            ListTheme
@@ -41,11 +43,21 @@ type RootTheme struct {
 	StackedBarChart StackedBarChartTheme
 	Tab             TabTheme
 	Table           TableTheme
+	ScrollView      ScrollViewTheme
+	SplitPane       SplitPaneTheme
 }
 
 type BlockTheme struct {
-	Title  Style
-	Border Style
+	Title         Style
+	Border        Style
+	FocusedBorder Style
+	BorderSet     BorderSet
+	Background    Style
+	Shadow        Style
+}
+
+type LayersTheme struct {
+	Backdrop Style
 }
 
 type BarChartTheme struct {
@@ -54,6 +66,12 @@ type BarChartTheme struct {
 	Labels []Style
 }
 
+type DialogTheme struct {
+	Text           Style
+	Button         Style
+	SelectedButton Style
+}
+
 type GaugeTheme struct {
 	Bar   Color
 	Label Style
@@ -108,14 +126,33 @@ type TableTheme struct {
 	Text Style
 }
 
+type SplitPaneTheme struct {
+	Divider         Style
+	SelectedDivider Style
+}
+
+type ScrollViewTheme struct {
+	Scrollbar Style
+	Thumb     Style
+}
+
 // Theme holds the default Styles and Colors for all widgets.
 // You can set default widget Styles by modifying the Theme before creating the widgets.
 var Theme = RootTheme{
 	Default: NewStyle(ColorWhite),
 
 	Block: BlockTheme{
-		Title:  NewStyle(ColorWhite),
-		Border: NewStyle(ColorWhite),
+		Title:         NewStyle(ColorWhite),
+		Border:        NewStyle(ColorWhite),
+		FocusedBorder: NewStyle(ColorCyan),
+		BorderSet:     BorderSetSingle,
+		Background:    StyleClear,
+		Shadow:        NewStyle(ColorClear, Color(8)),
+	},
+
+	Layers: LayersTheme{
+		// gray text, as not every backend can display ModifierDim
+		Backdrop: NewStyle(Color(8), ColorClear, ModifierDim),
 	},
 
 	BarChart: BarChartTheme{
@@ -124,6 +161,12 @@ var Theme = RootTheme{
 		Labels: StandardStyles,
 	},
 
+	Dialog: DialogTheme{
+		Text:           NewStyle(ColorWhite),
+		Button:         NewStyle(ColorWhite),
+		SelectedButton: NewStyle(ColorBlack, ColorWhite),
+	},
+
 	Paragraph: ParagraphTheme{
 		Text: NewStyle(ColorWhite),
 	},
@@ -173,6 +216,16 @@ var Theme = RootTheme{
 		Text: NewStyle(ColorWhite),
 	},
 
+	ScrollView: ScrollViewTheme{
+		Scrollbar: NewStyle(ColorWhite),
+		Thumb:     NewStyle(ColorWhite),
+	},
+
+	SplitPane: SplitPaneTheme{
+		Divider:         NewStyle(ColorWhite),
+		SelectedDivider: NewStyle(ColorCyan),
+	},
+
 	Tab: TabTheme{
 		Active:   NewStyle(ColorRed),
 		Inactive: NewStyle(ColorWhite),
@@ -0,0 +1,116 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui
+
+import (
+	"image"
+)
+
+// BlockTitle is an additional title drawn on the top or bottom border of a Block,
+// like a footer showing a position or a key hint.
+type BlockTitle struct {
+	// Text is parsed with ParseStyles, so that parts of it can have their own style.
+	Text      string
+	Style     Style
+	Alignment Alignment
+	Bottom    bool
+}
+
+// titleRow returns the titles drawn on the top or bottom border, grouped by alignment.
+func (self *Block) titleRow(bottom bool) [3][][]Cell {
+	var groups [3][][]Cell
+	if self.Title != "" && !bottom {
+		groups[self.TitleAlignment] = append(groups[self.TitleAlignment], ParseStyles(self.Title, self.TitleStyle))
+	}
+	for _, title := range self.Titles {
+		if title.Text != "" && title.Bottom == bottom {
+			groups[title.Alignment] = append(groups[title.Alignment], ParseStyles(title.Text, title.Style))
+		}
+	}
+	return groups
+}
+
+// cellsWidth returns the number of columns taken by the cells.
+func cellsWidth(cells []Cell) int {
+	return CellsSize(cells).X
+}
+
+// joinTitles joins the titles of a group, separated by a border cell.
+func joinTitles(titles [][]Cell, separator Cell) []Cell {
+	cells := []Cell{}
+	for i, title := range titles {
+		if i > 0 {
+			cells = append(cells, separator)
+		}
+		cells = append(cells, title...)
+	}
+	return cells
+}
+
+// titlesWidth returns the width needed by the titles of the widest border, with their margins.
+func (self *Block) titlesWidth() int {
+	width := 0
+	for _, bottom := range []bool{false, true} {
+		rowWidth := 0
+		for _, group := range self.titleRow(bottom) {
+			if len(group) == 0 {
+				continue
+			}
+			if rowWidth > 0 {
+				rowWidth++
+			}
+			rowWidth += cellsWidth(joinTitles(group, Cell{}))
+		}
+		if rowWidth > 0 {
+			width = MaxInt(width, rowWidth+4)
+		}
+	}
+	return width
+}
+
+// drawTitles draws the titles of the top or bottom border, leaving a border cell on each side.
+// The left titles are kept first, then the right ones, and the centered ones fit in between,
+// all of them being truncated with an ellipsis when the block is too narrow.
+func (self *Block) drawTitles(buf *Buffer, bottom bool) {
+	frame := self.frame()
+	y, side := frame.Min.Y, BorderSideTop
+	if bottom {
+		y, side = frame.Max.Y-1, BorderSideBottom
+	}
+	separator := Cell{' ', StyleClear}
+	if self.Border && ((bottom && self.BorderBottom) || (!bottom && self.BorderTop)) {
+		separator = Cell{self.BorderSet.orDefault().Horizontal, self.borderStyle(side)}
+	}
+
+	groups := self.titleRow(bottom)
+	left := joinTitles(groups[AlignLeft], separator)
+	center := joinTitles(groups[AlignCenter], separator)
+	right := joinTitles(groups[AlignRight], separator)
+
+	minX, maxX := frame.Min.X+2, frame.Max.X-2
+	available := maxX - minX
+	left = TrimCells(left, available)
+	leftEnd := minX + cellsWidth(left)
+	if len(left) > 0 {
+		leftEnd++
+	}
+	right = TrimCells(right, maxX-leftEnd)
+	rightStart := maxX - cellsWidth(right)
+	if len(right) > 0 {
+		rightStart--
+	}
+	center = TrimCells(center, rightStart-leftEnd)
+	centerStart := minX + (available-cellsWidth(center))/2
+	centerStart = MaxInt(MinInt(centerStart, rightStart-cellsWidth(center)), leftEnd)
+
+	for _, title := range []struct {
+		cells []Cell
+		x     int
+	}{{left, minX}, {center, centerStart}, {right, maxX - cellsWidth(right)}} {
+		for _, cx := range BuildCellWithXArray(title.cells) {
+			buf.SetCell(cx.Cell, image.Pt(title.x+cx.X, y))
+		}
+	}
+}
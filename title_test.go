@@ -0,0 +1,63 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package termui_test
+
+import (
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func newTitledBlock(titles ...BlockTitle) *Block {
+	block := NewBlock()
+	block.Titles = titles
+	return block
+}
+
+func TestTitleAlignment(t *testing.T) {
+	block := newTitledBlock(
+		BlockTitle{Text: "left"},
+		BlockTitle{Text: "mid", Alignment: AlignCenter},
+		BlockTitle{Text: "right", Alignment: AlignRight},
+	)
+	termuitest.AssertGolden(t, "title_alignment", block, 24, 3)
+}
+
+func TestTitleBottom(t *testing.T) {
+	block := newTitledBlock(
+		BlockTitle{Text: "1/10", Alignment: AlignRight, Bottom: true},
+		BlockTitle{Text: "q quit", Bottom: true},
+	)
+	block.Title = "top"
+	termuitest.AssertGolden(t, "title_bottom", block, 20, 3)
+}
+
+func TestTitleSegments(t *testing.T) {
+	block := newTitledBlock(
+		BlockTitle{Text: "[a](fg:red)", Style: NewStyle(ColorBlue)},
+		BlockTitle{Text: "b [c](mod:bold)", Style: NewStyle(ColorGreen)},
+		BlockTitle{Text: "d", Style: NewStyle(ColorYellow), Alignment: AlignRight},
+	)
+	termuitest.AssertStyledGolden(t, "title_segments", block, 14, 3)
+}
+
+func TestTitleEllipsis(t *testing.T) {
+	block := newTitledBlock(
+		BlockTitle{Text: "left"},
+		BlockTitle{Text: "center", Alignment: AlignCenter},
+		BlockTitle{Text: "right", Alignment: AlignRight},
+	)
+	termuitest.AssertGolden(t, "title_ellipsis", block, 16, 3)
+}
+
+func TestTitleNarrowLeftWins(t *testing.T) {
+	block := newTitledBlock(
+		BlockTitle{Text: "a long title"},
+		BlockTitle{Text: "center", Alignment: AlignCenter},
+		BlockTitle{Text: "right", Alignment: AlignRight},
+	)
+	termuitest.AssertGolden(t, "title_narrow", block, 8, 3)
+}
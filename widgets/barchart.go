@@ -87,3 +87,10 @@ func (self *BarChart) Draw(buf *Buffer) {
 		barXCoordinate += (self.BarWidth + self.BarGap)
 	}
 }
+
+// PreferredSize implements the Sizer interface: every bar and a row of labels.
+// A bar chart has no natural height, its bars grow with the height it is given.
+func (self *BarChart) PreferredSize(width int) image.Point {
+	size := image.Pt(len(self.Data)*(self.BarWidth+self.BarGap)-self.BarGap, 2)
+	return self.OuterSize(image.Pt(MaxInt(size.X, 0), size.Y))
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"testing"
+
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func TestBarChart(t *testing.T) {
+	bc := NewBarChart()
+	bc.Title = "Bars"
+	bc.Data = []float64{3, 7, 1, 5}
+	bc.Labels = []string{"a", "b", "c", "d"}
+	bc.BarWidth = 4
+	termuitest.AssertStyledGolden(t, "barchart", bc, 24, 10)
+}
+
+func TestBarChartMaxVal(t *testing.T) {
+	bc := NewBarChart()
+	bc.Data = []float64{2, 4}
+	bc.Labels = []string{"first", "second"}
+	bc.BarWidth = 6
+	bc.MaxVal = 8
+	termuitest.AssertGolden(t, "barchart_maxval", bc, 18, 8)
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+const (
+	benchmarkWidth  = 200
+	benchmarkHeight = 60
+)
+
+func newBenchmarkTable() *Table {
+	table := NewTable()
+	for i := 0; i < benchmarkHeight; i++ {
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprintf("Item #%d", i), "[AAA](fg:red)", "123", "CCCCC", "EEEEE", "GGGGG",
+		})
+	}
+	table.SetRect(0, 0, benchmarkWidth, benchmarkHeight)
+	return table
+}
+
+func newBenchmarkPlot() *Plot {
+	plot := NewPlot()
+	data := make([]float64, 2*benchmarkWidth)
+	for i := range data {
+		data[i] = 1 + math.Sin(float64(i)/5)
+	}
+	plot.Data = [][]float64{data}
+	plot.SetRect(0, 0, benchmarkWidth, benchmarkHeight)
+	return plot
+}
+
+func benchmarkDraw(b *testing.B, d Drawable) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := NewBuffer(d.GetRect())
+		d.Draw(buf)
+	}
+}
+
+func benchmarkRender(b *testing.B, d Drawable) {
+	SetBackend(NewSimulationBackend(benchmarkWidth, benchmarkHeight))
+	defer SetBackend(NewTermboxBackend())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Invalidate()
+		Render(d)
+	}
+}
+
+func BenchmarkNewBuffer(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewBuffer(image.Rect(0, 0, benchmarkWidth, benchmarkHeight))
+	}
+}
+
+func BenchmarkTableDraw(b *testing.B) {
+	benchmarkDraw(b, newBenchmarkTable())
+}
+
+func BenchmarkPlotDraw(b *testing.B) {
+	benchmarkDraw(b, newBenchmarkPlot())
+}
+
+func BenchmarkTableRender(b *testing.B) {
+	benchmarkRender(b, newBenchmarkTable())
+}
+
+func BenchmarkPlotRender(b *testing.B) {
+	benchmarkRender(b, newBenchmarkPlot())
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"image"
+
+	rw "github.com/mattn/go-runewidth"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+// dialogButtonGap is the number of cells between two buttons.
+const dialogButtonGap = 2
+
+// Dialog shows a message above a row of buttons. It is meant to be pushed on the
+// LayerModal of a Layers, which centers it and closes it on <Escape>.
+type Dialog struct {
+	Block
+	Text                string
+	TextStyle           Style
+	Buttons             []string
+	ButtonStyle         Style
+	SelectedButtonStyle Style
+	SelectedButton      int
+	// OnSelect is called with the index of the button chosen with <Enter>, <Space> or a click.
+	OnSelect func(int)
+}
+
+func NewDialog(text string, buttons ...string) *Dialog {
+	d := &Dialog{
+		Block:               *NewBlock(),
+		Text:                text,
+		TextStyle:           Theme.Dialog.Text,
+		Buttons:             buttons,
+		ButtonStyle:         Theme.Dialog.Button,
+		SelectedButtonStyle: Theme.Dialog.SelectedButton,
+	}
+	d.PaddingLeft, d.PaddingRight = 1, 1
+	return d
+}
+
+func dialogButtonLabel(name string) string {
+	return "[ " + name + " ]"
+}
+
+// buttonsWidth returns the width of the row of buttons.
+func (self *Dialog) buttonsWidth() int {
+	width := 0
+	for i, name := range self.Buttons {
+		if i > 0 {
+			width += dialogButtonGap
+		}
+		width += rw.StringWidth(dialogButtonLabel(name))
+	}
+	return width
+}
+
+// buttonRects returns the rects of the buttons, centered on the last row of Inner.
+func (self *Dialog) buttonRects() []image.Rectangle {
+	rects := make([]image.Rectangle, len(self.Buttons))
+	x := self.Inner.Min.X + (self.Inner.Dx()-self.buttonsWidth())/2
+	y := self.Inner.Max.Y - 1
+	for i, name := range self.Buttons {
+		width := rw.StringWidth(dialogButtonLabel(name))
+		rects[i] = image.Rect(x, y, x+width, y+1)
+		x += width + dialogButtonGap
+	}
+	return rects
+}
+
+func (self *Dialog) Draw(buf *Buffer) {
+	self.Block.Draw(buf)
+
+	textBottom := self.Inner.Max.Y
+	if len(self.Buttons) > 0 {
+		textBottom -= 2
+	}
+	cells := WrapCells(ParseStyles(self.Text, self.TextStyle), uint(MaxInt(self.Inner.Dx(), 1)))
+	for y, row := range SplitCells(cells, '\n') {
+		if y+self.Inner.Min.Y >= textBottom {
+			break
+		}
+		row = TrimCells(row, self.Inner.Dx())
+		for _, cx := range BuildCellWithXArray(row) {
+			buf.SetCell(cx.Cell, image.Pt(cx.X, y).Add(self.Inner.Min))
+		}
+	}
+
+	for i, rect := range self.buttonRects() {
+		style := self.ButtonStyle
+		if i == self.SelectedButton {
+			style = self.SelectedButtonStyle
+		}
+		buf.SetString(dialogButtonLabel(self.Buttons[i]), style, rect.Min)
+	}
+}
+
+// PreferredSize implements the Sizer interface: the text wrapped to the given width,
+// a blank row and the row of buttons.
+func (self *Dialog) PreferredSize(width int) image.Point {
+	cells := ParseStyles(self.Text, self.TextStyle)
+	textWidth := MinInt(self.InnerWidth(width), MaxInt(CellsSize(cells).X, self.buttonsWidth()))
+	size := CellsSize(WrapCells(cells, uint(MaxInt(textWidth, 1))))
+	if len(self.Buttons) > 0 {
+		size.X = MaxInt(size.X, self.buttonsWidth())
+		size.Y += 2
+	}
+	return self.OuterSize(size)
+}
+
+func (self *Dialog) selectButton() {
+	if self.OnSelect != nil && self.SelectedButton < len(self.Buttons) {
+		self.OnSelect(self.SelectedButton)
+	}
+}
+
+// HandleEvent implements the Focusable interface: <Left>/<Right>, h/l and <Tab>/<S-<Tab>>
+// move between the buttons, and <Enter>, <Space> or a click choose one.
+func (self *Dialog) HandleEvent(e Event) bool {
+	if len(self.Buttons) == 0 {
+		return false
+	}
+	switch e.ID {
+	case "<Left>", "h", "<S-<Tab>>":
+		self.SelectedButton = MaxInt(self.SelectedButton-1, 0)
+	case "<Right>", "l", "<Tab>":
+		self.SelectedButton = MinInt(self.SelectedButton+1, len(self.Buttons)-1)
+	case "<Enter>", "<Space>":
+		self.selectButton()
+	case "<MouseLeft>":
+		m, _ := e.Payload.(Mouse)
+		if m.Action != MouseActionPress {
+			return false
+		}
+		for i, rect := range self.buttonRects() {
+			if image.Pt(m.X, m.Y).In(rect) {
+				self.SelectedButton = i
+				self.selectButton()
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+	return true
+}
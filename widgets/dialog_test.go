@@ -0,0 +1,101 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"image"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func TestDialogCentered(t *testing.T) {
+	dialog := NewDialog("Quit?", "Yes", "No")
+	dialog.Title = "Confirm"
+	layers := NewLayers()
+	layers.Backdrop = StyleClear
+	layers.Push(LayerModal, dialog)
+	termuitest.AssertStyledGolden(t, "dialog", layers, 30, 9)
+
+	size := dialog.PreferredSize(30)
+	if size != image.Pt(19, 5) {
+		t.Errorf("expected a preferred size of %v, got %v", image.Pt(19, 5), size)
+	}
+	if rect := dialog.GetRect(); rect.Size() != size || rect.Min != image.Pt(5, 2) {
+		t.Errorf("expected the dialog to be centered at its preferred size, got %v", rect)
+	}
+}
+
+func TestDialogKeys(t *testing.T) {
+	dialog := NewDialog("Save?", "Yes", "No", "Cancel")
+	selected := []int{}
+	dialog.OnSelect = func(i int) { selected = append(selected, i) }
+
+	testCases := []struct {
+		id       string
+		used     bool
+		expected int
+	}{
+		{"<Left>", true, 0},
+		{"<Right>", true, 1},
+		{"l", true, 2},
+		{"<Tab>", true, 2},
+		{"h", true, 1},
+		{"<S-<Tab>>", true, 0},
+		{"<S-<Tab>>", true, 0},
+		{"<Tab>", true, 1},
+		{"j", false, 1},
+	}
+	for _, tc := range testCases {
+		if used := dialog.HandleEvent(Event{Type: KeyboardEvent, ID: tc.id}); used != tc.used {
+			t.Errorf("%s: expected used %v, got %v", tc.id, tc.used, used)
+		}
+		if dialog.SelectedButton != tc.expected {
+			t.Errorf("%s: expected button %d, got %d", tc.id, tc.expected, dialog.SelectedButton)
+		}
+	}
+
+	dialog.HandleEvent(Event{Type: KeyboardEvent, ID: "<Enter>"})
+	dialog.HandleEvent(Event{Type: KeyboardEvent, ID: "<Right>"})
+	dialog.HandleEvent(Event{Type: KeyboardEvent, ID: "<Space>"})
+	if len(selected) != 2 || selected[0] != 1 || selected[1] != 2 {
+		t.Errorf("expected buttons 1 and 2 to be chosen, got %v", selected)
+	}
+
+	empty := NewDialog("Nothing")
+	if empty.HandleEvent(Event{Type: KeyboardEvent, ID: "<Enter>"}) {
+		t.Error("expected a dialog without buttons to ignore events")
+	}
+}
+
+func TestDialogClick(t *testing.T) {
+	dialog := NewDialog("Quit?", "Yes", "No")
+	dialog.SetRect(0, 0, 19, 5)
+	selected := -1
+	dialog.OnSelect = func(i int) { selected = i }
+	// the buttons `[ Yes ]  [ No ]` are centered on the last inner row
+	click := func(x, y int, action MouseAction) bool {
+		return dialog.HandleEvent(Event{
+			Type:    MouseEvent,
+			ID:      "<MouseLeft>",
+			Payload: Mouse{X: x, Y: y, Button: MouseButtonLeft, Action: action},
+		})
+	}
+
+	if !click(12, 3, MouseActionPress) || selected != 1 || dialog.SelectedButton != 1 {
+		t.Errorf("expected a click to choose the second button, got %d", selected)
+	}
+	if !click(2, 3, MouseActionPress) || selected != 0 || dialog.SelectedButton != 0 {
+		t.Errorf("expected a click to choose the first button, got %d", selected)
+	}
+	selected = -1
+	if click(9, 3, MouseActionPress) || click(12, 1, MouseActionPress) || click(12, 3, MouseActionRelease) {
+		t.Error("expected clicks outside of the buttons and releases to be ignored")
+	}
+	if selected != -1 {
+		t.Errorf("expected no button to be chosen, got %d", selected)
+	}
+}
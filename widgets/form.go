@@ -47,6 +47,12 @@ type FormItem interface {
 	string() string
 }
 
+// pasteHandler is implemented by the FormItems accepting pasted text.
+// Pasted text is ignored by the other items.
+type pasteHandler interface {
+	handlePaste(string)
+}
+
 // FormNode is a form node.
 type FormNode struct {
 	Item     FormItem
@@ -267,7 +273,16 @@ func (self *Form) ToggleExpand() {
 }
 
 // HandleKeyboard handle special events that don't need to mapped by hand.
+// Pasted text is sent at once to the selected item.
 func (self *Form) HandleKeyboard(e Event) {
+	if e.Type == PasteEvent {
+		node := self.rows[self.selectedRow]
+		paste, _ := e.Payload.(Paste)
+		if item, ok := node.Item.(pasteHandler); ok && paste.Text != "" {
+			item.handlePaste(paste.Text)
+		}
+		return
+	}
 	if e.Type != KeyboardEvent {
 		return
 	}
@@ -281,3 +296,33 @@ func (self *Form) HandleKeyboard(e Event) {
 
 	node.Item.handleInput(formEvent(s))
 }
+
+// HandleEvent implements the Focusable interface: <Up>, <Down> and the mouse wheel
+// select an item, and the keyboard and paste events are sent to the selected item.
+func (self *Form) HandleEvent(e Event) bool {
+	if len(self.rows) == 0 {
+		return false
+	}
+	switch e.ID {
+	case "<Up>", "<MouseWheelUp>":
+		self.ScrollUp()
+		return true
+	case "<Down>", "<MouseWheelDown>":
+		self.ScrollDown()
+		return true
+	}
+	if e.Type != KeyboardEvent && e.Type != PasteEvent {
+		return false
+	}
+	self.HandleKeyboard(e)
+	return true
+}
+
+// PreferredSize implements the Sizer interface: the visible nodes, one per row.
+func (self *Form) PreferredSize(width int) image.Point {
+	size := image.Pt(0, len(self.rows))
+	for _, node := range self.rows {
+		size.X = MaxInt(size.X, CellsSize(node.parseStyles(self.TextStyle)).X)
+	}
+	return self.OuterSize(size)
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+func TestFormPaste(t *testing.T) {
+	field := NewTextField("name")
+	form := NewForm()
+	form.SetNodes([]*FormNode{{Item: field}})
+
+	form.HandleEvent(Event{Type: PasteEvent, ID: "<Paste>", Payload: Paste{Text: "hello"}})
+	if field.Answer() != "hello" {
+		t.Errorf("expected %q, got %q", "hello", field.Answer())
+	}
+
+	// a PasteEvent built without a Paste payload is ignored
+	form.HandleEvent(Event{Type: PasteEvent, ID: "<Paste>", Payload: "world"})
+	form.HandleEvent(Event{Type: PasteEvent, ID: "<Paste>"})
+	if field.Answer() != "hello" {
+		t.Errorf("expected %q, got %q", "hello", field.Answer())
+	}
+}
@@ -117,3 +117,12 @@ func (self *Gauge) Draw(buf *Buffer) {
 		}
 	}
 }
+
+// PreferredSize implements the Sizer interface: a single row wide enough for the label.
+func (self *Gauge) PreferredSize(width int) image.Point {
+	label := self.Label
+	if label == "" {
+		label = "100%"
+	}
+	return self.OuterSize(image.Pt(len(label), 1))
+}
@@ -17,6 +17,10 @@ type Image struct {
 	Monochrome          bool
 	MonochromeThreshold uint8
 	MonochromeInvert    bool
+	// ColorMode is the ColorMode of the terminal, detected by NewImage.
+	// In ColorMode16, the colors are too far from the image to keep its brightness,
+	// which is shown by shaded blocks instead.
+	ColorMode ColorMode
 }
 
 func NewImage(img image.Image) *Image {
@@ -24,6 +28,7 @@ func NewImage(img image.Image) *Image {
 		Block:               *NewBlock(),
 		MonochromeThreshold: 128,
 		Image:               img,
+		ColorMode:           DetectColorMode(),
 	}
 }
 
@@ -93,10 +98,11 @@ func (self *Image) Draw(buf *Buffer) {
 					by*imageHeight/bufHeight,
 					(by+1)*imageHeight/bufHeight,
 				)
-				buf.SetCell(
-					NewCell(c.ch(), NewStyle(c.fgColor(), ColorBlack)),
-					image.Pt(self.Inner.Min.X+bx, self.Inner.Min.Y+by),
-				)
+				cell := NewCell(SHADED_BLOCKS[4], NewStyle(c.fgColor(), ColorBlack))
+				if self.ColorMode == ColorMode16 {
+					cell = NewCell(c.ch(), NewStyle(c.fgColor().Downgrade(ColorMode16), ColorBlack))
+				}
+				buf.SetCell(cell, image.Pt(self.Inner.Min.X+bx, self.Inner.Min.Y+by))
 			}
 		}
 	}
@@ -142,10 +148,14 @@ func (self colorAverager) RGBA() (uint32, uint32, uint32, uint32) {
 		uint32(self.asum/self.count) & 0xffff
 }
 
+// fgColor returns the average as a 24-bit Color, which is downgraded by the backend
+// if the terminal doesn't support truecolor.
 func (self colorAverager) fgColor() Color {
-	return palette.Convert(self).(paletteColor).attribute
+	r, g, b, _ := self.RGBA()
+	return ColorRGB(uint8(r>>8), uint8(g>>8), uint8(b>>8))
 }
 
+// ch returns a shaded block as dense as the average is bright.
 func (self colorAverager) ch() rune {
 	gray := color.GrayModel.Convert(self).(color.Gray).Y
 	switch {
@@ -166,26 +176,6 @@ func (self colorAverager) monochrome(threshold uint8, invert bool) bool {
 	return self.count != 0 && (color.GrayModel.Convert(self).(color.Gray).Y < threshold != invert)
 }
 
-type paletteColor struct {
-	rgba      color.RGBA
-	attribute Color
-}
-
-func (self paletteColor) RGBA() (uint32, uint32, uint32, uint32) {
-	return self.rgba.RGBA()
-}
-
-var palette = color.Palette([]color.Color{
-	paletteColor{color.RGBA{0, 0, 0, 255}, ColorBlack},
-	paletteColor{color.RGBA{255, 0, 0, 255}, ColorRed},
-	paletteColor{color.RGBA{0, 255, 0, 255}, ColorGreen},
-	paletteColor{color.RGBA{255, 255, 0, 255}, ColorYellow},
-	paletteColor{color.RGBA{0, 0, 255, 255}, ColorBlue},
-	paletteColor{color.RGBA{255, 0, 255, 255}, ColorMagenta},
-	paletteColor{color.RGBA{0, 255, 255, 255}, ColorCyan},
-	paletteColor{color.RGBA{255, 255, 255, 255}, ColorWhite},
-})
-
 func blocksChar(ul, ur, ll, lr colorAverager, threshold uint8, invert bool) rune {
 	index := 0
 	if ul.monochrome(threshold, invert) {
@@ -202,3 +192,16 @@ func blocksChar(ul, ur, ll, lr colorAverager, threshold uint8, invert bool) rune
 	}
 	return IRREGULAR_BLOCKS[index]
 }
+
+// PreferredSize implements the Sizer interface: the size at which the image is not scaled down.
+// A cell holds 2x2 pixels in monochrome mode and is twice as high as wide otherwise.
+func (self *Image) PreferredSize(width int) image.Point {
+	if self.Image == nil {
+		return self.OuterSize(image.Point{})
+	}
+	bounds := self.Image.Bounds()
+	if self.Monochrome {
+		return self.OuterSize(image.Pt(bounds.Dx()/2, bounds.Dy()/2))
+	}
+	return self.OuterSize(image.Pt(bounds.Dx(), bounds.Dy()/2))
+}
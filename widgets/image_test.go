@@ -0,0 +1,40 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func newTestImage() *Image {
+	img := image.NewRGBA(image.Rect(0, 0, 5, 1))
+	for x, c := range []color.RGBA{{0, 0, 0, 255}, {80, 80, 80, 255}, {200, 0, 0, 255}, {0, 180, 255, 255}, {255, 255, 255, 255}} {
+		img.Set(x, 0, c)
+	}
+	widget := NewImage(img)
+	widget.Border = false
+	return widget
+}
+
+func TestImageColors(t *testing.T) {
+	testCases := []struct {
+		name string
+		mode ColorMode
+	}{
+		{"image_truecolor", ColorModeTrueColor},
+		{"image_256", ColorMode256},
+		{"image_16", ColorMode16},
+	}
+	for _, tc := range testCases {
+		widget := newTestImage()
+		widget.ColorMode = tc.mode
+		termuitest.AssertStyledGolden(t, tc.name, widget, 5, 1)
+	}
+}
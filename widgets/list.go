@@ -134,3 +134,54 @@ func (self *List) ScrollTop() {
 func (self *List) ScrollBottom() {
 	self.SelectedRow = len(self.Rows) - 1
 }
+
+// HandleEvent implements the Focusable interface: arrows, j/k, page and half page keys
+// and the mouse wheel scroll the list, and a click selects a row.
+func (self *List) HandleEvent(e Event) bool {
+	if len(self.Rows) == 0 {
+		return false
+	}
+	switch e.ID {
+	case "<Up>", "k", "<MouseWheelUp>":
+		self.ScrollUp()
+	case "<Down>", "j", "<MouseWheelDown>":
+		self.ScrollDown()
+	case "<PageUp>":
+		self.ScrollPageUp()
+	case "<PageDown>":
+		self.ScrollPageDown()
+	case "<C-u>":
+		self.ScrollHalfPageUp()
+	case "<C-d>":
+		self.ScrollHalfPageDown()
+	case "<Home>", "g":
+		self.ScrollTop()
+	case "<End>", "G":
+		self.ScrollBottom()
+	case "<MouseLeft>":
+		m, _ := e.Payload.(Mouse)
+		row := self.topRow + m.Y - self.Inner.Min.Y
+		if self.WrapText || m.Action != MouseActionPress || !image.Pt(m.X, m.Y).In(self.Inner) || row >= len(self.Rows) {
+			return false
+		}
+		self.SelectedRow = row
+	default:
+		return false
+	}
+	return true
+}
+
+// PreferredSize implements the Sizer interface: every row, wrapped to the given width if WrapText is set.
+func (self *List) PreferredSize(width int) image.Point {
+	size := image.Point{}
+	for _, row := range self.Rows {
+		cells := ParseStyles(row, self.TextStyle)
+		if self.WrapText {
+			cells = WrapCells(cells, uint(MaxInt(self.InnerWidth(width), 1)))
+		}
+		rowSize := CellsSize(cells)
+		size.X = MaxInt(size.X, rowSize.X)
+		size.Y += rowSize.Y
+	}
+	return self.OuterSize(size)
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+)
+
+func TestListClick(t *testing.T) {
+	list := NewList()
+	list.Rows = []string{"a", "b", "c", "d", "e", "f"}
+	list.SetRect(0, 0, 10, 5)
+
+	click := func(x, y int) Event {
+		return Event{
+			Type:    MouseEvent,
+			ID:      "<MouseLeft>",
+			Payload: Mouse{X: x, Y: y, Button: MouseButtonLeft, Action: MouseActionPress},
+		}
+	}
+	testCases := []struct {
+		x, y     int
+		used     bool
+		expected int
+	}{
+		{2, 1, true, 0},
+		{2, 3, true, 2},
+		{2, 0, false, 2},
+		{2, 4, false, 2},
+		{0, 2, false, 2},
+		{9, 2, false, 2},
+	}
+	for _, tc := range testCases {
+		used := list.HandleEvent(click(tc.x, tc.y))
+		if used != tc.used || list.SelectedRow != tc.expected {
+			t.Errorf("click at %d, %d: expected %v and row %d, got %v and row %d",
+				tc.x, tc.y, tc.used, tc.expected, used, list.SelectedRow)
+		}
+	}
+}
@@ -46,3 +46,12 @@ func (self *Paragraph) Draw(buf *Buffer) {
 		}
 	}
 }
+
+// PreferredSize implements the Sizer interface: the text wrapped to the given width if WrapText is set.
+func (self *Paragraph) PreferredSize(width int) image.Point {
+	cells := ParseStyles(self.Text, self.TextStyle)
+	if self.WrapText {
+		cells = WrapCells(cells, uint(MaxInt(self.InnerWidth(width), 1)))
+	}
+	return self.OuterSize(CellsSize(cells))
+}
@@ -79,6 +79,13 @@ func (self *PieChart) Draw(buf *Buffer) {
 	}
 }
 
+// PreferredSize implements the Sizer interface: a round pie as wide as the given width.
+// A pie chart has no natural size, it is scaled to the size it is given.
+func (self *PieChart) PreferredSize(width int) image.Point {
+	radius := int(float64(self.InnerWidth(width)/2) / xStretch)
+	return self.OuterSize(image.Pt(self.InnerWidth(width), 2*radius+1))
+}
+
 type circle struct {
 	image.Point
 	radius float64
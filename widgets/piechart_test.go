@@ -0,0 +1,30 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func TestPieChart(t *testing.T) {
+	pc := NewPieChart()
+	pc.Title = "Pie"
+	pc.Data = []float64{1, 2, 3}
+	pc.LabelFormatter = func(i int, v float64) string {
+		return fmt.Sprintf("%.0f", v)
+	}
+	termuitest.AssertStyledGolden(t, "piechart", pc, 30, 15)
+}
+
+func TestPieChartPreferredSize(t *testing.T) {
+	pc := NewPieChart()
+	if size := pc.PreferredSize(22); size != image.Pt(22, 13) {
+		t.Errorf("expected a round pie of %v, got %v", image.Pt(22, 13), size)
+	}
+}
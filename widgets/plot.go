@@ -225,3 +225,18 @@ func (self *Plot) Draw(buf *Buffer) {
 		self.renderDot(buf, drawArea, maxVal)
 	}
 }
+
+// PreferredSize implements the Sizer interface: every data point and the axes.
+// A plot has no natural height, it is scaled to the height it is given.
+func (self *Plot) PreferredSize(width int) image.Point {
+	size := image.Pt(0, 1)
+	for _, line := range self.Data {
+		if len(line) > 0 {
+			size.X = MaxInt(size.X, (len(line)-1)*self.HorizontalScale+1)
+		}
+	}
+	if self.ShowAxes {
+		size = size.Add(image.Pt(yAxisLabelsWidth+1, xAxisLabelsHeight+1))
+	}
+	return self.OuterSize(size)
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"testing"
+
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func TestPlotLineChart(t *testing.T) {
+	plot := NewPlot()
+	plot.Title = "Line"
+	plot.Data = [][]float64{
+		{0, 1, 2, 3, 4, 5, 4, 3, 2, 1, 0, 1, 2, 3},
+		{5, 4, 3, 2, 1, 0, 1, 2, 3, 4, 5, 4, 3, 2},
+	}
+	termuitest.AssertStyledGolden(t, "plot_line", plot, 30, 10)
+}
+
+func TestPlotDotMarker(t *testing.T) {
+	plot := NewPlot()
+	plot.Marker = MarkerDot
+	plot.Data = [][]float64{{1, 3, 2, 5, 4, 6, 3, 1}}
+	termuitest.AssertGolden(t, "plot_dot", plot, 24, 8)
+}
+
+func TestPlotScatter(t *testing.T) {
+	plot := NewPlot()
+	plot.PlotType = ScatterPlot
+	plot.Marker = MarkerDot
+	plot.ShowAxes = false
+	plot.Data = [][]float64{{1, 3, 2, 5, 4, 6, 3, 1}}
+	termuitest.AssertGolden(t, "plot_scatter", plot, 20, 6)
+}
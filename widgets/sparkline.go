@@ -92,3 +92,17 @@ func (self *SparklineGroup) Draw(buf *Buffer) {
 		}
 	}
 }
+
+// PreferredSize implements the Sizer interface: every data point, and a row per sparkline
+// in addition to its title. Sparklines grow with the height they are given.
+func (self *SparklineGroup) PreferredSize(width int) image.Point {
+	size := image.Point{}
+	for _, sl := range self.Sparklines {
+		size.X = MaxInt(size.X, len(sl.Data))
+		size.Y++
+		if sl.Title != "" {
+			size.Y++
+		}
+	}
+	return self.OuterSize(size)
+}
@@ -134,3 +134,27 @@ func (self *Table) Draw(buf *Buffer) {
 		}
 	}
 }
+
+// PreferredSize implements the Sizer interface: the columns are as wide as their widest cell
+// unless ColumnWidths is set.
+func (self *Table) PreferredSize(width int) image.Point {
+	columnWidths := self.ColumnWidths
+	if len(columnWidths) == 0 {
+		for _, row := range self.Rows {
+			for j, col := range row {
+				if j >= len(columnWidths) {
+					columnWidths = append(columnWidths, 0)
+				}
+				columnWidths[j] = MaxInt(columnWidths[j], CellsSize(ParseStyles(col, self.TextStyle)).X)
+			}
+		}
+	}
+	size := image.Pt(SumIntSlice(columnWidths), len(self.Rows))
+	if len(columnWidths) > 0 {
+		size.X += len(columnWidths) - 1
+	}
+	if self.RowSeparator && len(self.Rows) > 0 {
+		size.Y += len(self.Rows) - 1
+	}
+	return self.OuterSize(size)
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func newTestTable() *Table {
+	table := NewTable()
+	table.Title = "Table"
+	table.Rows = [][]string{
+		{"Name", "Size", "Type"},
+		{"termui", "42", "[dir](fg:blue)"},
+		{"a very long file name", "7", "file"},
+	}
+	return table
+}
+
+func TestTable(t *testing.T) {
+	termuitest.AssertStyledGolden(t, "table", newTestTable(), 30, 7)
+}
+
+func TestTableStyles(t *testing.T) {
+	table := newTestTable()
+	table.RowSeparator = false
+	table.TextAlignment = AlignCenter
+	table.RowStyles[0] = NewStyle(ColorWhite, ColorClear, ModifierBold)
+	table.FillRow = true
+	table.RowStyles[1] = NewStyle(ColorBlack, ColorYellow)
+	termuitest.AssertStyledGolden(t, "table_styles", table, 30, 5)
+}
+
+func TestTableColumnWidths(t *testing.T) {
+	table := newTestTable()
+	table.ColumnWidths = []int{8, 4, 6}
+	termuitest.AssertGolden(t, "table_widths", table, 20, 7)
+}
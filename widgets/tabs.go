@@ -7,6 +7,8 @@ package widgets
 import (
 	"image"
 
+	rw "github.com/mattn/go-runewidth"
+
 	. "github.com/jcalmat/termui/v3"
 )
 
@@ -57,7 +59,7 @@ func (self *TabPane) Draw(buf *Buffer) {
 			image.Pt(xCoordinate, self.Inner.Min.Y),
 		)
 
-		xCoordinate += 1 + len(name)
+		xCoordinate += 1 + rw.StringWidth(name)
 
 		if i < len(self.TabNames)-1 && xCoordinate < self.Inner.Max.X {
 			buf.SetCell(
@@ -69,3 +71,28 @@ func (self *TabPane) Draw(buf *Buffer) {
 		xCoordinate += 2
 	}
 }
+
+// HandleEvent implements the Focusable interface: <Left>/h and <Right>/l change the active tab.
+func (self *TabPane) HandleEvent(e Event) bool {
+	switch e.ID {
+	case "<Left>", "h":
+		self.FocusLeft()
+	case "<Right>", "l":
+		self.FocusRight()
+	default:
+		return false
+	}
+	return true
+}
+
+// PreferredSize implements the Sizer interface: the tab names on a single line.
+func (self *TabPane) PreferredSize(width int) image.Point {
+	size := image.Pt(0, 1)
+	for i, name := range self.TabNames {
+		if i > 0 {
+			size.X += 3
+		}
+		size.X += rw.StringWidth(name)
+	}
+	return self.OuterSize(size)
+}
@@ -0,0 +1,22 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"image"
+	"testing"
+
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+func TestTabPaneWideNames(t *testing.T) {
+	tabs := NewTabPane("日本", "ab", "語")
+	tabs.ActiveTabIndex = 1
+	size := tabs.PreferredSize(40)
+	if size != image.Pt(16, 3) {
+		t.Errorf("expected preferred size %v, got %v", image.Pt(16, 3), size)
+	}
+	termuitest.AssertGolden(t, "tabs_wide", tabs, size.X, size.Y)
+}
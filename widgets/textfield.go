@@ -15,6 +15,7 @@ type TextField struct {
 }
 
 var _ FormItem = (*TextField)(nil)
+var _ pasteHandler = (*TextField)(nil)
 
 // NewTextField creates a new instance of TextField object
 func NewTextField(question string) *TextField {
@@ -68,12 +69,23 @@ func (t *TextField) handleInput(e formEvent) {
 		return
 	}
 
-	for _, c := range e {
+	t.insert(string(e))
+}
+
+// handlePaste inserts the pasted text at once, dropping newlines and other special chars.
+func (t *TextField) handlePaste(text string) {
+	t.insert(text)
+}
+
+func (t *TextField) insert(s string) {
+	inserted := make([]byte, 0, len(s))
+	for _, c := range s {
 		if c >= 32 && c <= 126 {
-			t.input = fmt.Sprintf("%s%s%s", t.input[:t.cursorPosition], string(c), t.input[t.cursorPosition:])
-			t.cursorPosition++
+			inserted = append(inserted, byte(c))
 		}
 	}
+	t.input = fmt.Sprintf("%s%s%s", t.input[:t.cursorPosition], inserted, t.input[t.cursorPosition:])
+	t.cursorPosition += len(inserted)
 	t.setCursorPosition()
 }
 
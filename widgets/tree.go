@@ -255,3 +255,48 @@ func (self *Tree) CollapseAll() {
 	})
 	self.prepareNodes()
 }
+
+// HandleEvent implements the Focusable interface: arrows, j/k, page and half page keys
+// and the mouse wheel scroll the tree, <Enter> and <Space> toggle the selected node,
+// and <Left> and <Right> collapse and expand it.
+func (self *Tree) HandleEvent(e Event) bool {
+	if len(self.rows) == 0 {
+		return false
+	}
+	switch e.ID {
+	case "<Up>", "k", "<MouseWheelUp>":
+		self.ScrollUp()
+	case "<Down>", "j", "<MouseWheelDown>":
+		self.ScrollDown()
+	case "<PageUp>":
+		self.ScrollPageUp()
+	case "<PageDown>":
+		self.ScrollPageDown()
+	case "<C-u>":
+		self.ScrollHalfPageUp()
+	case "<C-d>":
+		self.ScrollHalfPageDown()
+	case "<Home>", "g":
+		self.ScrollTop()
+	case "<End>", "G":
+		self.ScrollBottom()
+	case "<Enter>", "<Space>":
+		self.ToggleExpand()
+	case "<Left>", "h":
+		self.Collapse()
+	case "<Right>", "l":
+		self.Expand()
+	default:
+		return false
+	}
+	return true
+}
+
+// PreferredSize implements the Sizer interface: the visible nodes, one per row.
+func (self *Tree) PreferredSize(width int) image.Point {
+	size := image.Pt(0, len(self.rows))
+	for _, node := range self.rows {
+		size.X = MaxInt(size.X, CellsSize(node.parseStyles(self.TextStyle)).X)
+	}
+	return self.OuterSize(size)
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 Zack Guo <zack.y.guo@gmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT license that can
+// be found in the LICENSE file.
+
+package widgets
+
+import (
+	"testing"
+
+	. "github.com/jcalmat/termui/v3"
+	"github.com/jcalmat/termui/v3/termuitest"
+)
+
+type treeValue string
+
+func (self treeValue) String() string {
+	return string(self)
+}
+
+func newTestTree() *Tree {
+	tree := NewTree()
+	tree.Title = "Tree"
+	tree.SetNodes([]*TreeNode{
+		{
+			Value:    treeValue("root"),
+			Expanded: true,
+			Nodes: []*TreeNode{
+				{Value: treeValue("leaf")},
+				{
+					Value: treeValue("collapsed"),
+					Nodes: []*TreeNode{{Value: treeValue("hidden")}},
+				},
+			},
+		},
+		{Value: treeValue("[styled](fg:red)")},
+	})
+	return tree
+}
+
+func TestTree(t *testing.T) {
+	termuitest.AssertStyledGolden(t, "tree", newTestTree(), 20, 7)
+}
+
+func TestTreeScrolled(t *testing.T) {
+	tree := newTestTree()
+	tree.SelectedRowStyle = NewStyle(ColorBlack, ColorWhite)
+	tree.ExpandAll()
+	tree.ScrollDown()
+	tree.ScrollDown()
+	termuitest.AssertStyledGolden(t, "tree_scrolled", tree, 20, 4)
+}